@@ -0,0 +1,71 @@
+package structs
+
+import (
+	"fmt"
+	"math"
+)
+
+// earthRadius is the mean radius of the earth in meters
+const earthRadius = 6371008.8
+
+// DefaultAirSpeeds are the speeds, in meters per second, used to turn air distances into durations
+var DefaultAirSpeeds = map[VehicleProfile]float64{
+	ProfileCar:   50 / 3.6,
+	ProfileTruck: 40 / 3.6,
+}
+
+// AirDistance returns the great-circle distance between a and b in meters
+func AirDistance(a Coordinate, b Coordinate) float64 {
+	lat1, lat2 := a.Latitude*math.Pi/180, b.Latitude*math.Pi/180
+	dLat := lat2 - lat1
+	dLng := (b.Longitude - a.Longitude) * math.Pi / 180
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// AirDistanceMatrix builds the matrix between coordinates without a routing service. Distances holds the
+// air distances rounded to meters, Costs the same values as the air_distance travel cost expects, and
+// Durations the time to cover them in seconds at speed, given in meters per second.
+func AirDistanceMatrix(coordinates []Coordinate, speed float64) (Matrix, error) {
+	if speed <= 0 || math.IsNaN(speed) || math.IsInf(speed, 0) {
+		return Matrix{}, fmt.Errorf("the speed %v is invalid. Please ensure that it is a positive number of meters per second", speed)
+	}
+	matrix := Matrix{
+		Durations: make([][]uint64, len(coordinates)),
+		Costs:     make([][]uint64, len(coordinates)),
+		Distances: make([][]uint64, len(coordinates)),
+	}
+	for i, from := range coordinates {
+		matrix.Durations[i] = make([]uint64, len(coordinates))
+		matrix.Costs[i] = make([]uint64, len(coordinates))
+		matrix.Distances[i] = make([]uint64, len(coordinates))
+		for j, to := range coordinates {
+			distance := AirDistance(from, to)
+			matrix.Distances[i][j] = uint64(math.Round(distance))
+			matrix.Costs[i][j] = matrix.Distances[i][j]
+			matrix.Durations[i][j] = uint64(math.Round(distance / speed))
+		}
+	}
+	return matrix, nil
+}
+
+// AirDistanceMatrices builds an air distance matrix over the locations for every profile of speeds,
+// keyed by profile as in VehicleRoutingMsg.Matrices. A nil speeds falls back to DefaultAirSpeeds.
+func AirDistanceMatrices(locations *Locations, speeds map[VehicleProfile]float64) (map[string]Matrix, error) {
+	coordinates, err := locations.ParseCoordinates()
+	if err != nil {
+		return nil, err
+	}
+	if speeds == nil {
+		speeds = DefaultAirSpeeds
+	}
+	matrices := make(map[string]Matrix, len(speeds))
+	for profile, speed := range speeds {
+		matrix, err := AirDistanceMatrix(coordinates, speed)
+		if err != nil {
+			return nil, fmt.Errorf("profile %s: %w", profile, err)
+		}
+		matrices[string(profile)] = matrix
+	}
+	return matrices, nil
+}
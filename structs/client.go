@@ -2,15 +2,18 @@ package structs
 
 import (
 	"fmt"
+	"math"
 	"reflect"
+	"strconv"
 	"strings"
 )
 
 type Locations struct {
-	Id              uint64   `json:"id" binding:"required"`       // locations' id
-	AnyTypeLocation any      `json:"location" binding:"required"` // Indicate the coordinates that will be used for route optimization. Every coordinate is separated by “|”. The coordinate format is latitude,longitude . Example: 1.29360227,103.80828989|1.29360227,103.8273062
-	Approaches      []string `json:"approaches"`
-	Location        string   `json:"location_str" swaggerignore:"true"`
+	Id              uint64       `json:"id" binding:"required"`       // locations' id
+	AnyTypeLocation any          `json:"location" binding:"required"` // Indicate the coordinates that will be used for route optimization. Every coordinate is separated by “|”. The coordinate format is latitude,longitude . Example: 1.29360227,103.80828989|1.29360227,103.8273062
+	Approaches      []string     `json:"approaches"`
+	Location        string       `json:"location_str" swaggerignore:"true"`
+	Coordinates     []Coordinate `json:"-" swaggerignore:"true"` // Parsed from Location by ParseCoordinates
 }
 
 
@@ -39,6 +42,73 @@ func (l *Locations) ConvertLocation() error {
 	return nil
 }
 
+// ParseCoordinates parses Location into coordinates and keeps them in Coordinates. Every coordinate
+// that is malformed or out of range is reported with its index, the returned error is a ValidationErrors.
+func (l *Locations) ParseCoordinates() ([]Coordinate, error) {
+	if len(l.Location) == 0 && l.AnyTypeLocation != nil {
+		if err := l.ConvertLocation(); err != nil {
+			return nil, err
+		}
+	}
+	var errs ValidationErrors
+	parts := strings.Split(l.Location, "|")
+	coordinates := make([]Coordinate, 0, len(parts))
+	for i, part := range parts {
+		coordinate, err := parseCoordinate(part)
+		if err != nil {
+			errs = append(errs, ValidationError{
+				Path:     fmt.Sprintf("locations.location[%d]", i),
+				Code:     CodeInvalidCoordinate,
+				Severity: SeverityError,
+				Message:  err.Error(),
+			})
+			continue
+		}
+		coordinates = append(coordinates, coordinate)
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	l.Coordinates = coordinates
+	return coordinates, nil
+}
+
+// LatLngAt returns the coordinate at index in the form used by HeraldJob.Location and HeraldVehicle.Start/End.
+// It reuses the coordinates parsed by ParseCoordinates and only parses when that has not been done yet.
+func (l *Locations) LatLngAt(index uint64) ([]float64, error) {
+	if l.Coordinates == nil {
+		if _, err := l.ParseCoordinates(); err != nil {
+			return nil, err
+		}
+	}
+	if index >= uint64(len(l.Coordinates)) {
+		return nil, fmt.Errorf("invalid location index %d. The valid value range is [0, %d)", index, len(l.Coordinates))
+	}
+	return l.Coordinates[index].LatLng(), nil
+}
+
+func parseCoordinate(value string) (Coordinate, error) {
+	latLng := strings.Split(value, ",")
+	if len(latLng) != 2 {
+		return Coordinate{}, fmt.Errorf("the coordinate %q is not in the correct format. Please ensure that it is specified as latitude,longitude", value)
+	}
+	lat, err := strconv.ParseFloat(strings.TrimSpace(latLng[0]), 64)
+	if err != nil || math.IsNaN(lat) || math.IsInf(lat, 0) {
+		return Coordinate{}, fmt.Errorf("the latitude of coordinate %q is not a valid number", value)
+	}
+	lng, err := strconv.ParseFloat(strings.TrimSpace(latLng[1]), 64)
+	if err != nil || math.IsNaN(lng) || math.IsInf(lng, 0) {
+		return Coordinate{}, fmt.Errorf("the longitude of coordinate %q is not a valid number", value)
+	}
+	if lat < -90 || lat > 90 {
+		return Coordinate{}, fmt.Errorf("the latitude %v of coordinate %q is out of range. Please ensure that it is within [-90, 90]", lat, value)
+	}
+	if lng < -180 || lng > 180 {
+		return Coordinate{}, fmt.Errorf("the longitude %v of coordinate %q is out of range. Please ensure that it is within [-180, 180]", lng, value)
+	}
+	return Coordinate{Latitude: lat, Longitude: lng}, nil
+}
+
 type Job struct {
 	Id            uint64     `json:"id" binding:"required"`             // Indicate the job id. It cannot be duplicated to other Job’s id.
 	LocationIndex uint64     `json:"location_index" binding:"required"` // Indicate the index of location in Locations. The valid value range is [0, length of locations)
@@ -64,6 +134,7 @@ type Vehicle struct {
 	MaxTasks    *uint64      `json:"max_tasks"`             // Describe the max tasks can be assigned to this vehicle
 	Costs       VehicleCosts `json:"costs"`                 // Describe the cost configurations for a vehicle
 	Depot       *uint64      `json:"depot"`                 // Describe the depot assigned to this vehicle
+	Depots      []uint64     `json:"depots"`                // Describe the depots this vehicle may start and end at. The engine chooses among them
 	Description *string      `json:"description"`           // Describe this vehicle
 	SpeedFactor *float64     `json:"speed_factor,omitempty"`
 }
@@ -97,6 +168,7 @@ type Unassigned struct {
 	Id       uint64    `json:"id"`                 // Indicate the id of unassigned task
 	Type     string    `json:"type,omitempty"`     // Describe the coordinate of the unassigned task
 	Location []float64 `json:"location,omitempty"` // Describe the unassigned task type
+	Reason   string    `json:"reason,omitempty"`   // Describe why the task can not be assigned when it is known before solving
 }
 
 type Coordinate struct {
@@ -104,6 +176,11 @@ type Coordinate struct {
 	Longitude float64
 }
 
+// LatLng returns the coordinate as [latitude, longitude]
+func (c Coordinate) LatLng() []float64 {
+	return []float64{c.Latitude, c.Longitude}
+}
+
 type TimeWindow struct {
 	Start float64 `json:"start" binding:"required"`
 	End   float64 `json:"end" binding:"required"`
@@ -164,10 +241,11 @@ type ObjectiveOptions struct {
 }
 
 type RoutingOptions struct {
-	TrafficTimestamp *uint64 `json:"traffic_timestamp"`
-	TruckSize        *string `json:"truck_size"`
-	TruckWeight      *uint64 `json:"truck_weight"`
-	Mode             *string `json:"mode"`
+	TrafficTimestamp *uint64      `json:"traffic_timestamp"`
+	TruckSize        *string      `json:"truck_size"`       // Describe the truck height,width,length in centimeters
+	TruckWeight      *uint64      `json:"truck_weight"`     // Describe the truck weight in kilograms
+	TruckAxleCount   *uint64      `json:"truck_axle_count"` // Describe the number of axles of the truck
+	Mode             *RoutingMode `json:"mode"`             // Describe the routing mode: "car", "truck", or their aliases "4w" and "6w"
 }
 
 
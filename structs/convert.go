@@ -0,0 +1,131 @@
+package structs
+
+import (
+	"fmt"
+)
+
+// BuildVehicleRoutingMsg maps a request to the engine message. Location indices are resolved to the
+// coordinates parsed from input.Locations, the routing mode gives the profile of every vehicle and
+// matrices, keyed by profile, are attached as they are. input is expected to have passed
+// validations.Validate, whose normalized copy has no legacy fields left.
+func BuildVehicleRoutingMsg(input *OptimizationPostInput, matrices map[string]Matrix) (VehicleRoutingMsg, error) {
+	msg := VehicleRoutingMsg{
+		Jobs:      make([]HeraldJob, 0, len(input.Jobs)),
+		Shipments: make([]HeraldShipment, 0, len(input.Shipments)),
+		Vehicles:  make([]HeraldVehicle, 0, len(input.Vehicles)),
+		Matrices:  matrices,
+		Depots:    input.Depots,
+		Options:   input.Options,
+	}
+	if len(msg.Depots) == 0 {
+		msg.Depots = input.Depot
+	}
+
+	for _, job := range input.Jobs {
+		location, err := input.Locations.LatLngAt(job.LocationIndex)
+		if err != nil {
+			return msg, fmt.Errorf("job %d: %w", job.Id, err)
+		}
+		msg.Jobs = append(msg.Jobs, HeraldJob{
+			Id:            job.Id,
+			Description:   stringOrEmpty(job.Description),
+			Location:      location,
+			LocationIndex: job.LocationIndex,
+			Setup:         job.Setup,
+			Service:       job.Service,
+			Delivery:      job.Delivery,
+			Pickup:        job.Pickup,
+			Skills:        job.Skills,
+			Priority:      job.Priority,
+			TimeWindows:   timeWindowsOrLegacy(job.TimeWindows, job.TimeWindow),
+		})
+	}
+
+	for i, shipment := range input.Shipments {
+		if shipment.Pickup == nil || shipment.Delivery == nil {
+			return msg, fmt.Errorf("shipment at index %d is missing its pickup or delivery", i)
+		}
+		pickup, err := buildShipmentStep(input, shipment.Pickup)
+		if err != nil {
+			return msg, fmt.Errorf("shipment pickup %d: %w", shipment.Pickup.Id, err)
+		}
+		delivery, err := buildShipmentStep(input, shipment.Delivery)
+		if err != nil {
+			return msg, fmt.Errorf("shipment delivery %d: %w", shipment.Delivery.Id, err)
+		}
+		msg.Shipments = append(msg.Shipments, HeraldShipment{
+			Pickup:   pickup,
+			Delivery: delivery,
+			Amount:   shipment.Amount,
+			Skills:   shipment.Skills,
+			Priority: shipment.Priority,
+		})
+	}
+
+	profile := input.Options.Routing.ModeOrDefault().Profile()
+	for _, vehicle := range input.Vehicles {
+		heraldVehicle := HeraldVehicle{
+			Id:          vehicle.Id,
+			Profile:     profile,
+			Description: stringOrEmpty(vehicle.Description),
+			StartIndex:  vehicle.StartIndex,
+			EndIndex:    vehicle.EndIndex,
+			Capacity:    vehicle.Capacity,
+			Skills:      vehicle.Skills,
+			TimeWindow:  vehicle.TimeWindow,
+			Breaks:      vehicle.Breaks,
+			SpeedFactor: vehicle.SpeedFactor,
+			MaxTasks:    vehicle.MaxTasks,
+			Costs:       vehicle.Costs,
+			Depot:       vehicle.Depot,
+			Depots:      vehicle.Depots,
+		}
+		if vehicle.StartIndex != nil {
+			start, err := input.Locations.LatLngAt(*vehicle.StartIndex)
+			if err != nil {
+				return msg, fmt.Errorf("vehicle %d start: %w", vehicle.Id, err)
+			}
+			heraldVehicle.Start = start
+		}
+		if vehicle.EndIndex != nil {
+			end, err := input.Locations.LatLngAt(*vehicle.EndIndex)
+			if err != nil {
+				return msg, fmt.Errorf("vehicle %d end: %w", vehicle.Id, err)
+			}
+			heraldVehicle.End = end
+		}
+		msg.Vehicles = append(msg.Vehicles, heraldVehicle)
+	}
+	return msg, nil
+}
+
+func buildShipmentStep(input *OptimizationPostInput, step *ShipmentStep) (*HeraldShipmentStep, error) {
+	location, err := input.Locations.LatLngAt(step.LocationIndex)
+	if err != nil {
+		return nil, err
+	}
+	service := step.Service
+	return &HeraldShipmentStep{
+		Id:            step.Id,
+		Description:   stringOrEmpty(step.Description),
+		Location:      location,
+		LocationIndex: step.LocationIndex,
+		Setup:         step.Setup,
+		Service:       &service,
+		TimeWindows:   timeWindowsOrLegacy(step.TimeWindows, step.TimeWindow),
+	}, nil
+}
+
+func timeWindowsOrLegacy(timeWindows [][]uint64, legacy [][]uint64) [][]uint64 {
+	if len(timeWindows) == 0 {
+		return legacy
+	}
+	return timeWindows
+}
+
+func stringOrEmpty(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
@@ -0,0 +1,165 @@
+package structs
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func roundTripInput() *OptimizationPostInput {
+	start, end := uint64(0), uint64(2)
+	service, setup, priority, perHour := uint64(60), uint64(30), uint64(5), uint64(20)
+	maxTasks := uint64(10)
+	description := "front desk"
+	mode := ModeTruck
+	return &OptimizationPostInput{
+		Locations: Locations{Location: "1.0,2.0|3.0,4.0|5.0,6.0"},
+		Jobs: []Job{
+			{Id: 1, LocationIndex: 1, Service: &service, Setup: &setup, Delivery: []uint64{2}, Pickup: []uint64{1}, Skills: []uint64{7}, Priority: &priority, TimeWindows: [][]uint64{{100, 200}}, Description: &description},
+			{Id: 2, LocationIndex: 2, TimeWindow: [][]uint64{{300, 400}}},
+		},
+		Shipments: []Shipment{{
+			Pickup:   &ShipmentStep{Id: 3, LocationIndex: 1, Service: 15, TimeWindow: [][]uint64{{0, 50}}},
+			Delivery: &ShipmentStep{Id: 4, LocationIndex: 2, TimeWindows: [][]uint64{{60, 90}}},
+			Amount:   []uint64{3},
+			Skills:   []uint64{8},
+		}},
+		Vehicles: []Vehicle{{
+			Id:         9,
+			StartIndex: &start,
+			EndIndex:   &end,
+			Capacity:   []int64{10},
+			Skills:     []uint64{7, 8},
+			TimeWindow: []uint64{0, 1000},
+			Breaks:     []Break{{Id: 1, TimeWindows: [][]uint64{{400, 500}}, Service: 30}},
+			MaxTasks:   &maxTasks,
+			Costs:      VehicleCosts{Fixed: 100, PerHour: &perHour},
+			Depots:     []uint64{1, 2},
+		}},
+		Depots:  []Depot{{Id: 1, LocationIndex: 0}, {Id: 2, LocationIndex: 2}},
+		Options: OptimizationOptions{Routing: RoutingOptions{Mode: &mode}},
+	}
+}
+
+func TestBuildVehicleRoutingMsg(t *testing.T) {
+	input := roundTripInput()
+	matrices := map[string]Matrix{"truck": {Durations: [][]uint64{{0, 1, 2}, {1, 0, 1}, {2, 1, 0}}}}
+	msg, err := BuildVehicleRoutingMsg(input, matrices)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(msg.Jobs) != 2 {
+		t.Fatalf("expected 2 jobs, got %d", len(msg.Jobs))
+	}
+	job := msg.Jobs[0]
+	if job.Id != 1 || job.LocationIndex != 1 || !slices.Equal(job.Location, []float64{3, 4}) {
+		t.Errorf("job 1 is not located at index 1 (3, 4): %+v", job)
+	}
+	if job.Description != "front desk" || *job.Service != 60 || *job.Setup != 30 || *job.Priority != 5 {
+		t.Errorf("job 1 lost its description, service, setup or priority: %+v", job)
+	}
+	if !slices.Equal(job.Delivery, []uint64{2}) || !slices.Equal(job.Pickup, []uint64{1}) || !slices.Equal(job.Skills, []uint64{7}) {
+		t.Errorf("job 1 lost its amounts or skills: %+v", job)
+	}
+	if !reflect.DeepEqual(job.TimeWindows, [][]uint64{{100, 200}}) {
+		t.Errorf("expected the time windows of job 1, got %v", job.TimeWindows)
+	}
+	if !reflect.DeepEqual(msg.Jobs[1].TimeWindows, [][]uint64{{300, 400}}) {
+		t.Errorf("expected job 2 to fall back to its legacy time_window, got %v", msg.Jobs[1].TimeWindows)
+	}
+
+	if len(msg.Shipments) != 1 {
+		t.Fatalf("expected 1 shipment, got %d", len(msg.Shipments))
+	}
+	shipment := msg.Shipments[0]
+	if shipment.Pickup.Id != 3 || !slices.Equal(shipment.Pickup.Location, []float64{3, 4}) || *shipment.Pickup.Service != 15 {
+		t.Errorf("unexpected pickup: %+v", shipment.Pickup)
+	}
+	if !reflect.DeepEqual(shipment.Pickup.TimeWindows, [][]uint64{{0, 50}}) {
+		t.Errorf("expected the pickup to fall back to its legacy time_window, got %v", shipment.Pickup.TimeWindows)
+	}
+	if shipment.Delivery.Id != 4 || !slices.Equal(shipment.Delivery.Location, []float64{5, 6}) || !reflect.DeepEqual(shipment.Delivery.TimeWindows, [][]uint64{{60, 90}}) {
+		t.Errorf("unexpected delivery: %+v", shipment.Delivery)
+	}
+	if !slices.Equal(shipment.Amount, []uint64{3}) || !slices.Equal(shipment.Skills, []uint64{8}) {
+		t.Errorf("the shipment lost its amount or skills: %+v", shipment)
+	}
+
+	if len(msg.Vehicles) != 1 {
+		t.Fatalf("expected 1 vehicle, got %d", len(msg.Vehicles))
+	}
+	vehicle := msg.Vehicles[0]
+	if vehicle.Id != 9 || vehicle.Profile != ProfileTruck {
+		t.Errorf("expected vehicle 9 with the truck profile, got %d with %q", vehicle.Id, vehicle.Profile)
+	}
+	if !slices.Equal(vehicle.Start, []float64{1, 2}) || !slices.Equal(vehicle.End, []float64{5, 6}) || *vehicle.StartIndex != 0 || *vehicle.EndIndex != 2 {
+		t.Errorf("unexpected start or end: %v (%d) %v (%d)", vehicle.Start, *vehicle.StartIndex, vehicle.End, *vehicle.EndIndex)
+	}
+	if !slices.Equal(vehicle.Capacity, []int64{10}) || !slices.Equal(vehicle.Skills, []uint64{7, 8}) || !slices.Equal(vehicle.TimeWindow, []uint64{0, 1000}) || *vehicle.MaxTasks != 10 {
+		t.Errorf("the vehicle lost its capacity, skills, shift or max_tasks: %+v", vehicle)
+	}
+	if !reflect.DeepEqual(vehicle.Breaks, input.Vehicles[0].Breaks) {
+		t.Errorf("expected breaks %v, got %v", input.Vehicles[0].Breaks, vehicle.Breaks)
+	}
+	if vehicle.Costs.Fixed != 100 || vehicle.Costs.PerHour == nil || *vehicle.Costs.PerHour != 20 {
+		t.Errorf("unexpected costs: %+v", vehicle.Costs)
+	}
+	if !slices.Equal(vehicle.Depots, []uint64{1, 2}) || !reflect.DeepEqual(msg.Depots, input.Depots) {
+		t.Errorf("the depots were not carried over: %v %v", vehicle.Depots, msg.Depots)
+	}
+
+	if !reflect.DeepEqual(msg.Matrices, matrices) {
+		t.Errorf("expected matrices %v, got %v", matrices, msg.Matrices)
+	}
+}
+
+func TestBuildVehicleRoutingMsgDefaultsToCar(t *testing.T) {
+	input := roundTripInput()
+	input.Options.Routing.Mode = nil
+	msg, err := BuildVehicleRoutingMsg(input, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if msg.Vehicles[0].Profile != ProfileCar {
+		t.Errorf("expected the car profile without mode, got %q", msg.Vehicles[0].Profile)
+	}
+}
+
+func TestBuildVehicleRoutingMsgLegacyDepot(t *testing.T) {
+	input := roundTripInput()
+	input.Depot, input.Depots = input.Depots, nil
+	msg, err := BuildVehicleRoutingMsg(input, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(msg.Depots, input.Depot) {
+		t.Errorf("expected the legacy depot field to be used, got %v", msg.Depots)
+	}
+}
+
+func TestBuildVehicleRoutingMsgBadIndex(t *testing.T) {
+	cases := []struct {
+		name   string
+		modify func(input *OptimizationPostInput)
+		prefix string
+	}{
+		{"job", func(input *OptimizationPostInput) { input.Jobs[1].LocationIndex = 3 }, "job 2:"},
+		{"pickup", func(input *OptimizationPostInput) { input.Shipments[0].Pickup.LocationIndex = 7 }, "shipment pickup 3:"},
+		{"vehicle end", func(input *OptimizationPostInput) {
+			end := uint64(5)
+			input.Vehicles[0].EndIndex = &end
+		}, "vehicle 9 end:"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			input := roundTripInput()
+			tc.modify(input)
+			_, err := BuildVehicleRoutingMsg(input, nil)
+			if err == nil || !strings.HasPrefix(err.Error(), tc.prefix) {
+				t.Errorf("expected an error starting with %q, got %v", tc.prefix, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package structs
+
+// CostComponent is one term of the monetary cost of a route. Implement it to price more than
+// the vehicle costs of the request, e.g. a rate per kilometer or per task.
+type CostComponent interface {
+	Name() string
+	Cost(vehicle Vehicle, route HeraldRoute) float64
+}
+
+// CostModel sums its components over every route. The zero value prices nothing, use DefaultCostModel
+// for the vehicle costs of the request.
+type CostModel []CostComponent
+
+type CostBreakdown struct {
+	Total      float64            `json:"total"`      // Describe the sum of all components
+	Components map[string]float64 `json:"components"` // Describe the cost of every component, keyed by its name
+}
+
+type RouteCost struct {
+	Vehicle uint64 `json:"vehicle"` // Describe the id of the vehicle of this route
+	CostBreakdown
+}
+
+type SolutionCost struct {
+	CostBreakdown
+	Routes []RouteCost `json:"routes"` // Describe the cost of every route, in the order of the result
+}
+
+// FixedCost charges costs.fixed once for every vehicle that is used
+type FixedCost struct{}
+
+func (FixedCost) Name() string {
+	return "fixed"
+}
+
+func (FixedCost) Cost(vehicle Vehicle, route HeraldRoute) float64 {
+	return float64(vehicle.Costs.Fixed)
+}
+
+// TimeCost charges costs.per_hour, or the legacy costs.per_hours, for the travel duration of the route
+type TimeCost struct{}
+
+func (TimeCost) Name() string {
+	return "time"
+}
+
+func (TimeCost) Cost(vehicle Vehicle, route HeraldRoute) float64 {
+	perHour := vehicle.Costs.PerHour
+	if perHour == nil {
+		perHour = vehicle.Costs.PerHours
+	}
+	if perHour == nil || route.Duration == nil {
+		return 0
+	}
+	return float64(*perHour) * float64(*route.Duration) / 3600
+}
+
+// DefaultCostModel prices the vehicle costs of the request: fixed plus time based
+func DefaultCostModel() CostModel {
+	return CostModel{FixedCost{}, TimeCost{}}
+}
+
+// Route prices route driven by vehicle
+func (m CostModel) Route(vehicle Vehicle, route HeraldRoute) RouteCost {
+	cost := RouteCost{Vehicle: vehicle.Id, CostBreakdown: CostBreakdown{Components: make(map[string]float64, len(m))}}
+	for _, component := range m {
+		value := component.Cost(vehicle, route)
+		cost.Components[component.Name()] += value
+		cost.Total += value
+	}
+	return cost
+}
+
+// Evaluate prices every route of result with the vehicles of input and adds them up. Assign it to
+// result.Costs to expose the breakdown next to the summary. Routes of a vehicle missing from input are
+// priced with a zero vehicle.
+func (m CostModel) Evaluate(input *OptimizationPostInput, result *HeraldResult) *SolutionCost {
+	vehicles := make(map[uint64]Vehicle, len(input.Vehicles))
+	for _, vehicle := range input.Vehicles {
+		vehicles[vehicle.Id] = vehicle
+	}
+
+	solution := &SolutionCost{
+		CostBreakdown: CostBreakdown{Components: make(map[string]float64, len(m))},
+		Routes:        make([]RouteCost, 0, len(result.Routes)),
+	}
+	for _, route := range result.Routes {
+		var vehicle Vehicle
+		if route.Vehicle != nil {
+			vehicle = vehicles[*route.Vehicle]
+			vehicle.Id = *route.Vehicle
+		}
+		cost := m.Route(vehicle, route)
+		for name, value := range cost.Components {
+			solution.Components[name] += value
+		}
+		solution.Total += cost.Total
+		solution.Routes = append(solution.Routes, cost)
+	}
+	return solution
+}
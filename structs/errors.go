@@ -0,0 +1,68 @@
+package structs
+
+import (
+	"errors"
+	"strings"
+)
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+const CodeInvalidCoordinate = "invalid_coordinate"
+
+type ValidationError struct {
+	Path     string   `json:"path,omitempty"` // Describe the JSON path of the offending field. Example: jobs[12].time_windows[1]
+	Code     string   `json:"code"`           // Describe the stable machine-readable code of this error
+	Severity Severity `json:"severity"`       // Describe whether this is an error or a warning
+	Message  string   `json:"message"`        // Describe the error in a human readable way
+}
+
+func (e ValidationError) Error() string {
+	if len(e.Path) == 0 {
+		return e.Message
+	}
+	return e.Path + ": " + e.Message
+}
+
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	return strings.Join(e.Messages(), "; ")
+}
+
+// Messages flattens the list into the plain strings used by OptimizationPostOutput.Warning
+func (e ValidationErrors) Messages() []string {
+	messages := make([]string, 0, len(e))
+	for _, item := range e {
+		messages = append(messages, item.Error())
+	}
+	return messages
+}
+
+// AddWarnings appends warnings, such as the deprecation warnings of validations.Normalize, to the output
+func (o *OptimizationPostOutput) AddWarnings(warnings ValidationErrors) {
+	o.Warning = append(o.Warning, warnings.Messages()...)
+}
+
+// NewSimpleErrorResp builds the error response for err, keeping every structured error it carries
+func NewSimpleErrorResp(err error, warnings ValidationErrors) SimpleErrorResp {
+	resp := SimpleErrorResp{
+		Message:  err.Error(),
+		Warnings: warnings.Messages(),
+	}
+	var list ValidationErrors
+	var single ValidationError
+	if errors.As(err, &list) {
+		resp.Errors = list
+	} else if errors.As(err, &single) {
+		resp.Errors = ValidationErrors{single}
+	}
+	if len(resp.Warnings) == 0 {
+		resp.Warnings = nil
+	}
+	return resp
+}
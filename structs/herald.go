@@ -60,6 +60,7 @@ type HeraldResult struct { // will change to herald
 	Code       *uint8       `json:"code,omitempty"`       // 0: no error, 1: internal error, 2: input error, 3: routing error
 	Error      *string      `json:"error,omitempty"`      // Describe the error when there is
 	Summary    *Summary     `json:"summary"`              // Summarize the solution
+	Costs      *SolutionCost `json:"costs,omitempty"`      // Break down the monetary cost of the solution when it is evaluated
 	Unassigned []Unassigned `json:"unassigned,omitempty"` // Describe the unassigned tasks
 	Routes     []HeraldRoute `json:"routes"`               // Describe the optimization routes
 }
@@ -73,7 +74,7 @@ type OptimizationPostInput struct {
 	Options     OptimizationOptions `json:"options"`                      // Describes the optimization options
 	Depots      []Depot             `json:"depots"`                       // Describes the locations of depots
 	Depot       []Depot             `json:"depot" swaggerignore:"true"`
-	Mode        *string             `json:"mode"`
+	Mode        *RoutingMode        `json:"mode"`
 	CostMatrix  [][]uint64          `json:"cost_matrix" swaggerignore:"true"`
 }
 
@@ -163,8 +164,9 @@ type OptimizationGetOutput struct {
 
 
 type SimpleErrorResp struct {
-	Message  string   `json:"message"`
-	Warnings []string `json:"warnings,omitempty"`
+	Message  string           `json:"message"`
+	Warnings []string         `json:"warnings,omitempty"`
+	Errors   ValidationErrors `json:"errors,omitempty"` // Describe every validation error with the path of the offending field
 }
 
 
@@ -179,6 +181,7 @@ type VehicleRoutingMsg struct {
 type Matrix struct {
 	Durations [][]uint64 `json:"durations,omitempty"`
 	Costs     [][]uint64 `json:"costs,omitempty"`
+	Distances [][]uint64 `json:"distances,omitempty"`
 }
 
 type HeraldShipment struct { // will change to Herald
@@ -230,6 +233,7 @@ type HeraldVehicle struct { // will change to herald
 	Steps       []VehicleStep  `json:"steps,omitempty"`
 	Costs       VehicleCosts   `json:"costs"`
 	Depot       *uint64        `json:"depot,omitempty"`
+	Depots      []uint64       `json:"depots,omitempty"`
 }
 
 type Break struct {
@@ -239,6 +243,16 @@ type Break struct {
 	Description string     `json:"description,omitempty"`           // Describe this break
 }
 
+// Step types used by VehicleStep and HeraldStep
+const (
+	StepTypeStart    = "start"
+	StepTypeJob      = "job"
+	StepTypePickup   = "pickup"
+	StepTypeDelivery = "delivery"
+	StepTypeBreak    = "break"
+	StepTypeEnd      = "end"
+)
+
 type VehicleStep struct {
 	Type          string `json:"type" binding:"required"`
 	Id            uint64 `json:"id,omitempty"`
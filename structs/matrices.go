@@ -0,0 +1,68 @@
+package structs
+
+import (
+	"fmt"
+)
+
+const (
+	CodeMissingMatrix      = "missing_matrix"
+	CodeMatrixSizeMismatch = "matrix_size_mismatch"
+)
+
+// GroupVehiclesByProfile splits a mixed fleet by profile. It returns the profiles in the order of their
+// first vehicle, which are the profiles VehicleRoutingMsg.Matrices needs, and the vehicles of each.
+func GroupVehiclesByProfile(vehicles []HeraldVehicle) ([]VehicleProfile, map[VehicleProfile][]HeraldVehicle) {
+	var profiles []VehicleProfile
+	groups := map[VehicleProfile][]HeraldVehicle{}
+	for _, vehicle := range vehicles {
+		if _, ok := groups[vehicle.Profile]; !ok {
+			profiles = append(profiles, vehicle.Profile)
+		}
+		groups[vehicle.Profile] = append(groups[vehicle.Profile], vehicle)
+	}
+	return profiles, groups
+}
+
+// CheckMatrices checks that Matrices has an entry for the profile of every vehicle and that the durations,
+// costs and distances given in each are locationCount x locationCount. Matrices of unused profiles are
+// not checked. A non-nil error is always a ValidationErrors.
+func (msg *VehicleRoutingMsg) CheckMatrices(locationCount int) error {
+	var errs ValidationErrors
+	profiles, _ := GroupVehiclesByProfile(msg.Vehicles)
+	for _, profile := range profiles {
+		path := fmt.Sprintf("matrices.%s", profile)
+		matrix, ok := msg.Matrices[string(profile)]
+		if !ok {
+			errs = append(errs, ValidationError{path, CodeMissingMatrix, SeverityError, fmt.Sprintf("no matrix is given for the profile %q of the vehicles", profile)})
+			continue
+		}
+		tables := []struct {
+			name  string
+			table [][]uint64
+		}{{"durations", matrix.Durations}, {"costs", matrix.Costs}, {"distances", matrix.Distances}}
+		for _, item := range tables {
+			if item.table == nil {
+				continue
+			}
+			if err := checkMatrixSize(item.table, locationCount); err != nil {
+				errs = append(errs, ValidationError{path + "." + item.name, CodeMatrixSizeMismatch, SeverityError, err.Error()})
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func checkMatrixSize(table [][]uint64, locationCount int) error {
+	if len(table) != locationCount {
+		return fmt.Errorf("the matrix has %d rows while %d locations are given. Please ensure that it is %dx%d", len(table), locationCount, locationCount, locationCount)
+	}
+	for i, row := range table {
+		if len(row) != locationCount {
+			return fmt.Errorf("row %d of the matrix has %d entries while %d locations are given. Please ensure that it is %dx%d", i, len(row), locationCount, locationCount, locationCount)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package structs
+
+// taskInfo is what the result needs to know about a task of the request
+type taskInfo struct {
+	locationIndex uint64
+	description   *string
+}
+
+// Enrich maps the raw engine result back to the entities of input. It fills the step descriptions and
+// location indices, the route descriptions from the vehicles, the type and location of unassigned
+// tasks and the coordinates of every step. Values already set by the engine are kept. An unassigned
+// task without type whose id belongs to several kinds of task is left as it is.
+func (r *HeraldResult) Enrich(input *OptimizationPostInput) error {
+	if _, err := input.Locations.ParseCoordinates(); err != nil {
+		return err
+	}
+
+	tasks := map[string]map[uint64]taskInfo{
+		StepTypeJob:      {},
+		StepTypePickup:   {},
+		StepTypeDelivery: {},
+	}
+	for _, job := range input.Jobs {
+		tasks[StepTypeJob][job.Id] = taskInfo{job.LocationIndex, job.Description}
+	}
+	for _, shipment := range input.Shipments {
+		if shipment.Pickup != nil {
+			tasks[StepTypePickup][shipment.Pickup.Id] = taskInfo{shipment.Pickup.LocationIndex, shipment.Pickup.Description}
+		}
+		if shipment.Delivery != nil {
+			tasks[StepTypeDelivery][shipment.Delivery.Id] = taskInfo{shipment.Delivery.LocationIndex, shipment.Delivery.Description}
+		}
+	}
+	vehicles := make(map[uint64]Vehicle, len(input.Vehicles))
+	for _, vehicle := range input.Vehicles {
+		vehicles[vehicle.Id] = vehicle
+	}
+
+	for i := range r.Routes {
+		route := &r.Routes[i]
+		var vehicle *Vehicle
+		if route.Vehicle != nil {
+			if found, ok := vehicles[*route.Vehicle]; ok {
+				vehicle = &found
+			}
+		}
+		if vehicle != nil && route.Description == nil {
+			route.Description = vehicle.Description
+		}
+		for j := range route.Steps {
+			enrichStep(&route.Steps[j], input, tasks, vehicle)
+		}
+	}
+
+	for i := range r.Unassigned {
+		task := &r.Unassigned[i]
+		var info taskInfo
+		found := false
+		if len(task.Type) > 0 {
+			info, found = tasks[task.Type][task.Id]
+		} else {
+			// ids are only unique within a kind of task
+			var matches []string
+			for _, taskType := range []string{StepTypeJob, StepTypePickup, StepTypeDelivery} {
+				if _, ok := tasks[taskType][task.Id]; ok {
+					matches = append(matches, taskType)
+				}
+			}
+			if len(matches) == 1 {
+				task.Type = matches[0]
+				info, found = tasks[task.Type][task.Id]
+			}
+		}
+		if found && len(task.Location) == 0 {
+			task.Location, _ = input.Locations.LatLngAt(info.locationIndex)
+		}
+	}
+	return nil
+}
+
+func enrichStep(step *HeraldStep, input *OptimizationPostInput, tasks map[string]map[uint64]taskInfo, vehicle *Vehicle) {
+	if step.Type == nil {
+		return
+	}
+	switch *step.Type {
+	case StepTypeJob, StepTypePickup, StepTypeDelivery:
+		if step.Id == nil {
+			break
+		}
+		info, ok := tasks[*step.Type][*step.Id]
+		if !ok {
+			break
+		}
+		if step.Description == nil {
+			step.Description = info.description
+		}
+		if step.LocationIndex == nil {
+			locationIndex := info.locationIndex
+			step.LocationIndex = &locationIndex
+		}
+	case StepTypeBreak:
+		if vehicle == nil || step.Id == nil || step.Description != nil {
+			break
+		}
+		for _, b := range vehicle.Breaks {
+			if b.Id == *step.Id && len(b.Description) > 0 {
+				description := b.Description
+				step.Description = &description
+			}
+		}
+	case StepTypeStart:
+		if vehicle != nil && step.LocationIndex == nil {
+			step.LocationIndex = vehicle.StartIndex
+		}
+	case StepTypeEnd:
+		if vehicle != nil && step.LocationIndex == nil {
+			step.LocationIndex = vehicle.EndIndex
+		}
+	}
+	if step.LocationIndex != nil && len(step.Location) == 0 {
+		step.Location, _ = input.Locations.LatLngAt(*step.LocationIndex)
+	}
+}
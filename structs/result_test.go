@@ -0,0 +1,90 @@
+package structs
+
+import (
+	"slices"
+	"testing"
+)
+
+func enrichInput() *OptimizationPostInput {
+	start := uint64(0)
+	jobDescription, pickupDescription, vehicleDescription := "job", "pickup", "van"
+	return &OptimizationPostInput{
+		Locations: Locations{Location: "1.0,2.0|3.0,4.0|5.0,6.0"},
+		Jobs:      []Job{{Id: 1, LocationIndex: 1, Description: &jobDescription}, {Id: 5, LocationIndex: 2}},
+		Shipments: []Shipment{{
+			Pickup:   &ShipmentStep{Id: 1, LocationIndex: 2, Description: &pickupDescription},
+			Delivery: &ShipmentStep{Id: 2, LocationIndex: 0},
+		}},
+		Vehicles: []Vehicle{{Id: 9, StartIndex: &start, Description: &vehicleDescription, Breaks: []Break{{Id: 3, Description: "lunch"}}}},
+	}
+}
+
+func TestEnrichRoute(t *testing.T) {
+	vehicle, jobId, pickupId, breakId := uint64(9), uint64(1), uint64(1), uint64(3)
+	start, job, pickup, lunch := StepTypeStart, StepTypeJob, StepTypePickup, StepTypeBreak
+	result := HeraldResult{Routes: []HeraldRoute{{
+		Vehicle: &vehicle,
+		Steps:   []HeraldStep{{Type: &start}, {Type: &job, Id: &jobId}, {Type: &pickup, Id: &pickupId}, {Type: &lunch, Id: &breakId}},
+	}}}
+	if err := result.Enrich(enrichInput()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	route := result.Routes[0]
+	if route.Description == nil || *route.Description != "van" {
+		t.Errorf("expected the route to carry the vehicle description, got %v", route.Description)
+	}
+	expected := []struct {
+		locationIndex *uint64
+		location      []float64
+		description   string
+	}{
+		{ptr(uint64(0)), []float64{1, 2}, ""},
+		{ptr(uint64(1)), []float64{3, 4}, "job"},
+		{ptr(uint64(2)), []float64{5, 6}, "pickup"},
+		{nil, nil, "lunch"},
+	}
+	for i, want := range expected {
+		step := route.Steps[i]
+		if (step.LocationIndex == nil) != (want.locationIndex == nil) || (step.LocationIndex != nil && *step.LocationIndex != *want.locationIndex) {
+			t.Errorf("step %d: expected location index %v, got %v", i, want.locationIndex, step.LocationIndex)
+		}
+		if !slices.Equal(step.Location, want.location) {
+			t.Errorf("step %d: expected location %v, got %v", i, want.location, step.Location)
+		}
+		if description := stringOrEmpty(step.Description); description != want.description {
+			t.Errorf("step %d: expected description %q, got %q", i, want.description, description)
+		}
+	}
+}
+
+func TestEnrichUnassigned(t *testing.T) {
+	result := HeraldResult{Unassigned: []Unassigned{
+		{Id: 5},                       // only a job
+		{Id: 2},                       // only a delivery
+		{Id: 1},                       // a job and a pickup share the id
+		{Id: 1, Type: StepTypePickup}, // typed by the engine
+		{Id: 42},                      // unknown
+	}}
+	if err := result.Enrich(enrichInput()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []Unassigned{
+		{Id: 5, Type: StepTypeJob, Location: []float64{5, 6}},
+		{Id: 2, Type: StepTypeDelivery, Location: []float64{1, 2}},
+		{Id: 1},
+		{Id: 1, Type: StepTypePickup, Location: []float64{5, 6}},
+		{Id: 42},
+	}
+	for i, want := range expected {
+		got := result.Unassigned[i]
+		if got.Type != want.Type || !slices.Equal(got.Location, want.Location) {
+			t.Errorf("unassigned %d: expected type %q at %v, got type %q at %v", i, want.Type, want.Location, got.Type, got.Location)
+		}
+	}
+}
+
+func ptr[T any](value T) *T {
+	return &value
+}
@@ -0,0 +1,136 @@
+package structs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type RoutingMode string
+
+const (
+	ModeCar   RoutingMode = "car"
+	ModeTruck RoutingMode = "truck"
+	Mode4W    RoutingMode = "4w" // alias of car
+	Mode6W    RoutingMode = "6w" // alias of truck
+)
+
+const (
+	CodeInvalidMode           = "invalid_mode"
+	CodeInvalidTruckSize      = "invalid_truck_size"
+	CodeInvalidTruckWeight    = "invalid_truck_weight"
+	CodeInvalidTruckAxleCount = "invalid_truck_axle_count"
+)
+
+// Bounds of the truck profile. Sizes are in centimeters and the weight in kilograms.
+const (
+	MaxTruckHeight    uint64 = 1000
+	MaxTruckWidth     uint64 = 5000
+	MaxTruckLength    uint64 = 5000
+	MaxTruckWeight    uint64 = 100000
+	MaxTruckAxleCount uint64 = 20
+)
+
+const (
+	ProfileCar   VehicleProfile = "car"
+	ProfileTruck VehicleProfile = "truck"
+)
+
+// Valid reports whether m is one of the known modes or aliases
+func (m RoutingMode) Valid() bool {
+	switch m {
+	case ModeCar, ModeTruck, Mode4W, Mode6W:
+		return true
+	}
+	return false
+}
+
+// Canonical resolves the aliases, "4w" is car and "6w" is truck
+func (m RoutingMode) Canonical() RoutingMode {
+	switch m {
+	case Mode4W:
+		return ModeCar
+	case Mode6W:
+		return ModeTruck
+	}
+	return m
+}
+
+// Profile returns the engine profile of the mode
+func (m RoutingMode) Profile() VehicleProfile {
+	return VehicleProfile(m.Canonical())
+}
+
+type TruckProfile struct {
+	Height    uint64 // Describe the truck height in centimeters
+	Width     uint64 // Describe the truck width in centimeters
+	Length    uint64 // Describe the truck length in centimeters
+	Weight    uint64 // Describe the truck weight in kilograms. 0 means not given
+	AxleCount uint64 // Describe the number of axles. 0 means not given
+}
+
+// ModeOrDefault returns the routing mode, car when it is not given
+func (o RoutingOptions) ModeOrDefault() RoutingMode {
+	if o.Mode == nil || len(*o.Mode) == 0 {
+		return ModeCar
+	}
+	return *o.Mode
+}
+
+// TruckProfile parses truck_size, truck_weight and truck_axle_count. It returns nil when none of them is given.
+// Every invalid field is reported, the returned error is a ValidationErrors.
+func (o RoutingOptions) TruckProfile() (*TruckProfile, error) {
+	hasSize := o.TruckSize != nil && len(*o.TruckSize) > 0
+	if !hasSize && o.TruckWeight == nil && o.TruckAxleCount == nil {
+		return nil, nil
+	}
+
+	var errs ValidationErrors
+	invalid := func(path string, code string, format string, args ...any) {
+		errs = append(errs, ValidationError{Path: path, Code: code, Severity: SeverityError, Message: fmt.Sprintf(format, args...)})
+	}
+
+	profile := &TruckProfile{}
+	if hasSize {
+		truckSizes := strings.Split(*o.TruckSize, ",")
+		if len(truckSizes) != 3 {
+			invalid("options.routing.truck_size", CodeInvalidTruckSize, "the input for 'truck_size' is not in the correct format. Please ensure that 'truck_size' dimensions are specified as integer values")
+		} else {
+			names := []string{"height", "width", "length"}
+			bounds := []uint64{MaxTruckHeight, MaxTruckWidth, MaxTruckLength}
+			values := make([]uint64, 3)
+			for i, truckSize := range truckSizes {
+				value, err := strconv.ParseUint(strings.TrimSpace(truckSize), 10, 64)
+				if err != nil {
+					invalid("options.routing.truck_size", CodeInvalidTruckSize, "the truck %s %q in 'truck_size' is not an integer. Please ensure that 'truck_size' dimensions are specified as integer values in centimeters", names[i], truckSize)
+					continue
+				}
+				if value == 0 || value > bounds[i] {
+					invalid("options.routing.truck_size", CodeInvalidTruckSize, "the truck %s %d cm is out of range. Please provide a value in the range of [1, %d] centimeters", names[i], value, bounds[i])
+					continue
+				}
+				values[i] = value
+			}
+			profile.Height, profile.Width, profile.Length = values[0], values[1], values[2]
+		}
+	}
+	if o.TruckWeight != nil {
+		if *o.TruckWeight == 0 || *o.TruckWeight > MaxTruckWeight {
+			invalid("options.routing.truck_weight", CodeInvalidTruckWeight, "the truck weight %d kg is out of range. Please provide a value in the range of [1, %d] kilograms", *o.TruckWeight, MaxTruckWeight)
+		} else {
+			profile.Weight = *o.TruckWeight
+		}
+	}
+	if o.TruckAxleCount != nil {
+		if *o.TruckAxleCount == 0 || *o.TruckAxleCount > MaxTruckAxleCount {
+			invalid("options.routing.truck_axle_count", CodeInvalidTruckAxleCount, "the truck axle count %d is out of range. Please provide a value in the range of [1, %d]", *o.TruckAxleCount, MaxTruckAxleCount)
+		} else {
+			profile.AxleCount = *o.TruckAxleCount
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return profile, nil
+}
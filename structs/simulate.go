@@ -0,0 +1,232 @@
+package structs
+
+import (
+	"fmt"
+)
+
+// Causes of the violations reported by SimulateRoute
+const (
+	ViolationLeadTime = "lead_time" // service begins before the time window opens
+	ViolationDelay    = "delay"     // service begins after the time window closes
+	ViolationLoad     = "load"      // the load exceeds the capacity of the vehicle
+)
+
+// SimulateRoute drives vehicle through steps without calling the solver and returns the route the engine
+// would report: every step filled with its arrival, cumulative travel duration, setup, service, waiting time,
+// load and violations, and the route totals. Missing start and end steps are added from the start and end
+// of vehicle. The vehicle leaves at the beginning of its shift, waits for the next time window when it
+// arrives early, and is late rather than skipping a task when no window is left. service_at and
+// service_after of a step set the earliest start of its service, service_before the latest, and reaching a
+// step after its service_at is a delay. The cost is the duration, as for the engine. Cumulative distances
+// are filled when the matrix carries distances.
+func SimulateRoute(input *OptimizationPostInput, vehicle Vehicle, steps []VehicleStep, matrix Matrix) (HeraldRoute, error) {
+	vehicleId := vehicle.Id
+	route := HeraldRoute{Vehicle: &vehicleId, Description: vehicle.Description}
+	if _, err := input.Locations.ParseCoordinates(); err != nil {
+		return route, err
+	}
+	tasks := NewTaskIndex(input)
+	breaks := make(map[uint64]Break, len(vehicle.Breaks))
+	for _, b := range vehicle.Breaks {
+		breaks[b.Id] = b
+	}
+
+	if vehicle.StartIndex != nil && (len(steps) == 0 || steps[0].Type != StepTypeStart) {
+		steps = append([]VehicleStep{{Type: StepTypeStart}}, steps...)
+	}
+	if vehicle.EndIndex != nil && (len(steps) == 0 || steps[len(steps)-1].Type != StepTypeEnd) {
+		steps = append(steps, VehicleStep{Type: StepTypeEnd})
+	}
+
+	var jobIds []uint64
+	for _, step := range steps {
+		if step.Type == StepTypeJob {
+			jobIds = append(jobIds, step.Id)
+		}
+	}
+	load := tasks.InitialLoad(jobIds)
+
+	var now, duration, distance, setup, service, waitingTime, priority uint64
+	var delivered, pickedUp []uint64
+	if len(vehicle.TimeWindow) == 2 {
+		now = vehicle.TimeWindow[0]
+	}
+	var previous *uint64
+	for i, step := range steps {
+		var task Task
+		var location *uint64
+		switch step.Type {
+		case StepTypeStart:
+			location = vehicle.StartIndex
+		case StepTypeEnd:
+			location = vehicle.EndIndex
+			if len(vehicle.TimeWindow) == 2 {
+				task.TimeWindows = [][]uint64{vehicle.TimeWindow}
+			}
+		case StepTypeBreak:
+			b, ok := breaks[step.Id]
+			if !ok {
+				return route, fmt.Errorf("steps[%d]: break %d is not a break of vehicle %d", i, step.Id, vehicle.Id)
+			}
+			task = Task{Service: b.Service, TimeWindows: b.TimeWindows}
+			location = previous
+		case StepTypeJob, StepTypePickup, StepTypeDelivery:
+			var ok bool
+			if task, ok = tasks.Get(step.Type, step.Id); !ok {
+				return route, fmt.Errorf("steps[%d]: %s %d does not exist", i, step.Type, step.Id)
+			}
+			locationIndex := task.LocationIndex
+			location = &locationIndex
+		default:
+			return route, fmt.Errorf("steps[%d]: the step type %q is invalid", i, step.Type)
+		}
+
+		var travel uint64
+		if previous != nil && location != nil {
+			if *previous >= uint64(len(matrix.Durations)) || *location >= uint64(len(matrix.Durations[*previous])) {
+				return route, fmt.Errorf("steps[%d]: the duration matrix has no entry from location %d to location %d", i, *previous, *location)
+			}
+			travel = matrix.Durations[*previous][*location]
+			if len(matrix.Distances) > 0 {
+				if *previous >= uint64(len(matrix.Distances)) || *location >= uint64(len(matrix.Distances[*previous])) {
+					return route, fmt.Errorf("steps[%d]: the distance matrix has no entry from location %d to location %d", i, *previous, *location)
+				}
+				distance += matrix.Distances[*previous][*location]
+			}
+		}
+		duration += travel
+		arrival := now + travel
+		begin, violations := ServiceStart(arrival, step, task.TimeWindows)
+
+		stepType := step.Type
+		heraldStep := HeraldStep{
+			Type:          &stepType,
+			LocationIndex: location,
+			Violations:    violations,
+		}
+		if step.Type != StepTypeStart && step.Type != StepTypeEnd {
+			id := step.Id
+			heraldStep.Id = &id
+		}
+		if location != nil {
+			heraldStep.Location, _ = input.Locations.LatLngAt(*location)
+		}
+
+		var stepSetup uint64
+		if location != nil && (previous == nil || *previous != *location) {
+			stepSetup = task.Setup
+		}
+		waiting := begin - arrival
+		load = load.Serve(task)
+		if (step.Type == StepTypeStart || len(task.Unload)+len(task.Load) > 0) && load.Exceeds(vehicle.Capacity) {
+			heraldStep.Violations = append(heraldStep.Violations, newViolation(ViolationLoad, 0))
+		}
+		arrivalTime, cumulated := float64(arrival), float64(duration)
+		heraldStep.Arrival = &arrivalTime
+		heraldStep.Duration = &cumulated
+		heraldStep.Setup = &stepSetup
+		heraldStep.Service = &task.Service
+		heraldStep.WaitingTime = &waiting
+		heraldStep.Load = load.Values()
+		if len(matrix.Distances) > 0 {
+			cumulatedDistance := distance
+			heraldStep.Distance = &cumulatedDistance
+		}
+
+		setup += stepSetup
+		service += task.Service
+		waitingTime += waiting
+		switch step.Type {
+		case StepTypeJob:
+			delivered = addAmounts(delivered, task.Unload)
+			pickedUp = addAmounts(pickedUp, task.Load)
+			priority += task.Priority
+		case StepTypePickup:
+			pickedUp = addAmounts(pickedUp, task.Load)
+			priority += task.Priority
+		case StepTypeDelivery:
+			delivered = addAmounts(delivered, task.Unload)
+		}
+		route.Steps = append(route.Steps, heraldStep)
+		route.Violations = append(route.Violations, heraldStep.Violations...)
+
+		now = begin + stepSetup + task.Service
+		if location != nil {
+			previous = location
+		}
+	}
+
+	route.Cost = duration
+	route.Setup = &setup
+	route.Service = &service
+	route.Duration = &duration
+	route.WaitingTime = &waitingTime
+	route.Priority = &priority
+	route.Delivery = delivered
+	route.Pickup = pickedUp
+	if len(matrix.Distances) > 0 {
+		totalDistance := float64(distance)
+		route.Distance = &totalDistance
+	}
+	return route, nil
+}
+
+// ServiceStart returns when service begins for an arrival at arrival and the violations of that start.
+// Without a pinned start the vehicle waits for the next time window to open. A step reached after its
+// service_at or its service_before is late. A late step has a single delay, its largest lateness.
+func ServiceStart(arrival uint64, step VehicleStep, timeWindows [][]uint64) (uint64, []Violation) {
+	begin := max(arrival, step.ServiceAfter, step.ServiceAt)
+	if step.ServiceAt == 0 && step.ServiceAfter == 0 {
+		for _, timeWindow := range timeWindows {
+			if len(timeWindow) == 2 && begin <= timeWindow[1] {
+				begin = max(begin, timeWindow[0])
+				break
+			}
+		}
+	}
+
+	var violations []Violation
+	var delay uint64
+	late := false
+	if len(timeWindows) > 0 {
+		var next, lastEnd uint64
+		inside, hasNext := false, false
+		for _, timeWindow := range timeWindows {
+			if len(timeWindow) != 2 {
+				continue
+			}
+			if timeWindow[0] <= begin && begin <= timeWindow[1] {
+				inside = true
+				break
+			}
+			if timeWindow[0] > begin && (!hasNext || timeWindow[0] < next) {
+				next, hasNext = timeWindow[0], true
+			}
+			lastEnd = max(lastEnd, timeWindow[1])
+		}
+		if !inside && hasNext {
+			violations = append(violations, newViolation(ViolationLeadTime, next-begin))
+		} else if !inside {
+			delay, late = begin-lastEnd, true
+		}
+	}
+	if step.ServiceAt != 0 && arrival > step.ServiceAt {
+		delay, late = max(delay, arrival-step.ServiceAt), true
+	}
+	if step.ServiceBefore != 0 && begin > step.ServiceBefore {
+		delay, late = max(delay, begin-step.ServiceBefore), true
+	}
+	if late {
+		violations = append(violations, newViolation(ViolationDelay, delay))
+	}
+	return begin, violations
+}
+
+func newViolation(cause string, duration uint64) Violation {
+	seconds := float64(duration)
+	violation := Violation{Cause: &cause}
+	if duration > 0 {
+		violation.Duration = &seconds
+	}
+	return violation
+}
@@ -0,0 +1,132 @@
+package structs
+
+import (
+	"slices"
+	"testing"
+)
+
+func simulationInput() *OptimizationPostInput {
+	service := uint64(5)
+	return &OptimizationPostInput{
+		Locations: Locations{Location: "1.0,2.0|3.0,4.0"},
+		Jobs: []Job{
+			{Id: 1, LocationIndex: 1, TimeWindows: [][]uint64{{20, 30}}, Service: &service, Delivery: []uint64{2}},
+			{Id: 2, LocationIndex: 0, TimeWindows: [][]uint64{{0, 10}}},
+		},
+	}
+}
+
+func simulationMatrix() Matrix {
+	return Matrix{
+		Durations: [][]uint64{{0, 10}, {10, 0}},
+		Distances: [][]uint64{{0, 100}, {100, 0}},
+	}
+}
+
+func causes(violations []Violation) []string {
+	var names []string
+	for _, violation := range violations {
+		names = append(names, *violation.Cause)
+	}
+	return names
+}
+
+func TestSimulateRoute(t *testing.T) {
+	start := uint64(0)
+	vehicle := Vehicle{Id: 7, StartIndex: &start, EndIndex: &start, Capacity: []int64{3}, TimeWindow: []uint64{0, 40}}
+	route, err := SimulateRoute(simulationInput(), vehicle, []VehicleStep{{Type: StepTypeJob, Id: 1}, {Type: StepTypeJob, Id: 2}}, simulationMatrix())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []struct {
+		stepType   string
+		arrival    float64
+		waiting    uint64
+		distance   uint64
+		load       []float64
+		violations []string
+	}{
+		{StepTypeStart, 0, 0, 0, []float64{2}, nil},
+		{StepTypeJob, 10, 10, 100, []float64{0}, nil},
+		{StepTypeJob, 35, 0, 200, []float64{0}, []string{ViolationDelay}},
+		{StepTypeEnd, 35, 0, 200, []float64{0}, nil},
+	}
+	if len(route.Steps) != len(expected) {
+		t.Fatalf("expected %d steps, got %d", len(expected), len(route.Steps))
+	}
+	for i, want := range expected {
+		step := route.Steps[i]
+		if *step.Type != want.stepType || *step.Arrival != want.arrival || *step.WaitingTime != want.waiting || *step.Distance != want.distance {
+			t.Errorf("step %d: expected %s arriving at %v after waiting %d and %d meters, got %s at %v after %d and %d", i, want.stepType, want.arrival, want.waiting, want.distance, *step.Type, *step.Arrival, *step.WaitingTime, *step.Distance)
+		}
+		if !slices.Equal(step.Load, want.load) || !slices.Equal(causes(step.Violations), want.violations) {
+			t.Errorf("step %d: expected load %v and violations %v, got %v and %v", i, want.load, want.violations, step.Load, causes(step.Violations))
+		}
+	}
+	if *route.Steps[2].Violations[0].Duration != 25 {
+		t.Errorf("expected job 2 to be 25 seconds late, got %v", *route.Steps[2].Violations[0].Duration)
+	}
+	if route.Cost != 20 || *route.Duration != 20 || *route.Service != 5 || *route.WaitingTime != 10 || *route.Distance != 200 || !slices.Equal(route.Delivery, []uint64{2}) {
+		t.Errorf("unexpected route totals: %+v", route)
+	}
+}
+
+func TestSimulateRouteLoad(t *testing.T) {
+	cases := []struct {
+		name       string
+		capacity   []int64
+		violations []string
+	}{
+		{"without capacity", nil, nil},
+		{"within capacity", []int64{2}, nil},
+		{"over capacity", []int64{1}, []string{ViolationLoad}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			start := uint64(0)
+			vehicle := Vehicle{Id: 7, StartIndex: &start, Capacity: tc.capacity}
+			route, err := SimulateRoute(simulationInput(), vehicle, []VehicleStep{{Type: StepTypeJob, Id: 1}}, simulationMatrix())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := causes(route.Steps[0].Violations); !slices.Equal(got, tc.violations) {
+				t.Errorf("expected %v on the start step, got %v", tc.violations, got)
+			}
+		})
+	}
+}
+
+func TestServiceStart(t *testing.T) {
+	timeWindows := [][]uint64{{20, 30}, {50, 60}}
+	cases := []struct {
+		name       string
+		arrival    uint64
+		step       VehicleStep
+		begin      uint64
+		violations []string
+	}{
+		{"waits for the window", 10, VehicleStep{}, 20, nil},
+		{"waits for the next window", 35, VehicleStep{}, 50, nil},
+		{"late after the last window", 70, VehicleStep{}, 70, []string{ViolationDelay}},
+		{"service_at before a window", 10, VehicleStep{ServiceAt: 15}, 15, []string{ViolationLeadTime}},
+		{"reached after service_at", 25, VehicleStep{ServiceAt: 22}, 25, []string{ViolationDelay}},
+		{"reached after service_before", 55, VehicleStep{ServiceBefore: 40}, 55, []string{ViolationDelay}},
+		{"reached after service_at and the last window", 75, VehicleStep{ServiceAt: 65}, 75, []string{ViolationDelay}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			begin, violations := ServiceStart(tc.arrival, tc.step, timeWindows)
+			if begin != tc.begin || !slices.Equal(causes(violations), tc.violations) {
+				t.Errorf("expected service at %d with %v, got %d with %v", tc.begin, tc.violations, begin, causes(violations))
+			}
+		})
+	}
+}
+
+func TestServiceStartReportsLargestDelay(t *testing.T) {
+	_, violations := ServiceStart(75, VehicleStep{ServiceAt: 65}, [][]uint64{{20, 60}})
+	if len(violations) != 1 || violations[0].Duration == nil || *violations[0].Duration != 15 {
+		t.Errorf("expected a single delay of 15 seconds after the window, got %v", violations)
+	}
+}
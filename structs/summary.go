@@ -0,0 +1,118 @@
+package structs
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+const CodeSummaryMismatch = "summary_mismatch"
+
+// floatTolerance absorbs the rounding of durations and distances summed as floats
+const floatTolerance = 1e-3
+
+// ComputeSummary derives the totals of a solution from its routes. Per route totals the engine omitted
+// count as zero, and the delivery and pickup amounts are summed dimension by dimension.
+func ComputeSummary(routes []HeraldRoute, unassigned []Unassigned) Summary {
+	var cost, setup, service, waitingTime, priority uint64
+	var duration float64
+	summary := Summary{
+		Unassigned: uint64(len(unassigned)),
+	}
+	for _, route := range routes {
+		cost += route.Cost
+		setup += valueOrZero(route.Setup)
+		service += valueOrZero(route.Service)
+		waitingTime += valueOrZero(route.WaitingTime)
+		priority += valueOrZero(route.Priority)
+		duration += float64(valueOrZero(route.Duration))
+		if route.Distance != nil {
+			summary.Distance += *route.Distance
+		}
+		summary.Delivery = addAmounts(summary.Delivery, route.Delivery)
+		summary.Pickup = addAmounts(summary.Pickup, route.Pickup)
+		summary.Violations = append(summary.Violations, route.Violations...)
+	}
+	count := uint64(len(routes))
+	summary.Cost = &cost
+	summary.Routes = &count
+	summary.Setup = &setup
+	summary.Service = &service
+	summary.Duration = &duration
+	summary.WaitingTime = &waitingTime
+	summary.Priority = &priority
+	return summary
+}
+
+// CheckSummary compares the summary given by the engine with the one recomputed from the routes and
+// unassigned tasks, and reports every total that disagrees. Totals the engine omitted are not checked.
+func (r *HeraldResult) CheckSummary() ValidationErrors {
+	if r.Summary == nil {
+		if len(r.Routes) == 0 && len(r.Unassigned) == 0 {
+			return nil
+		}
+		return ValidationErrors{{"summary", CodeSummaryMismatch, SeverityError, "summary is missing while the result has routes or unassigned tasks"}}
+	}
+
+	var errs ValidationErrors
+	mismatch := func(field string, given any, computed any) {
+		errs = append(errs, ValidationError{
+			Path:     "summary." + field,
+			Code:     CodeSummaryMismatch,
+			Severity: SeverityError,
+			Message:  fmt.Sprintf("%s of the summary is %v while the routes add up to %v", field, given, computed),
+		})
+	}
+
+	given, computed := r.Summary, ComputeSummary(r.Routes, r.Unassigned)
+	uint64Totals := []struct {
+		field    string
+		given    *uint64
+		computed *uint64
+	}{
+		{"cost", given.Cost, computed.Cost},
+		{"routes", given.Routes, computed.Routes},
+		{"setup", given.Setup, computed.Setup},
+		{"service", given.Service, computed.Service},
+		{"waiting_time", given.WaitingTime, computed.WaitingTime},
+		{"priority", given.Priority, computed.Priority},
+	}
+	for _, total := range uint64Totals {
+		if total.given != nil && *total.given != *total.computed {
+			mismatch(total.field, *total.given, *total.computed)
+		}
+	}
+	if given.Unassigned != computed.Unassigned {
+		mismatch("unassigned", given.Unassigned, computed.Unassigned)
+	}
+	if given.Duration != nil && math.Abs(*given.Duration-*computed.Duration) > floatTolerance {
+		mismatch("duration", *given.Duration, *computed.Duration)
+	}
+	if math.Abs(given.Distance-computed.Distance) > floatTolerance {
+		mismatch("distance", given.Distance, computed.Distance)
+	}
+	if len(given.Delivery) > 0 && !slices.Equal(given.Delivery, computed.Delivery) {
+		mismatch("delivery", given.Delivery, computed.Delivery)
+	}
+	if len(given.Pickup) > 0 && !slices.Equal(given.Pickup, computed.Pickup) {
+		mismatch("pickup", given.Pickup, computed.Pickup)
+	}
+	return errs
+}
+
+func addAmounts(total []uint64, amount []uint64) []uint64 {
+	for len(total) < len(amount) {
+		total = append(total, 0)
+	}
+	for i, value := range amount {
+		total[i] += value
+	}
+	return total
+}
+
+func valueOrZero(value *uint64) uint64 {
+	if value == nil {
+		return 0
+	}
+	return *value
+}
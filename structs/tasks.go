@@ -0,0 +1,125 @@
+package structs
+
+// Task is what routing needs to know about a job, a shipment pickup or a shipment delivery.
+// A break is a Task with only Service and TimeWindows.
+type Task struct {
+	LocationIndex uint64
+	Setup         uint64
+	Service       uint64
+	TimeWindows   [][]uint64 // the legacy time_window when time_windows is not given
+	Skills        []uint64
+	Priority      uint64   // the priority of a shipment counts once, at its pickup
+	Unload        []uint64 // amount leaving the vehicle, job deliveries are on board from the start
+	Load          []uint64 // amount entering the vehicle
+	Pickup        *uint64  // for a shipment delivery, the id of its pickup
+}
+
+// TaskIndex finds the tasks of a request by step type and id. Ids are only unique within a type.
+type TaskIndex map[string]map[uint64]Task
+
+// NewTaskIndex indexes the jobs and shipments of input. Shipments missing their pickup or delivery are skipped.
+func NewTaskIndex(input *OptimizationPostInput) TaskIndex {
+	tasks := TaskIndex{
+		StepTypeJob:      {},
+		StepTypePickup:   {},
+		StepTypeDelivery: {},
+	}
+	for _, job := range input.Jobs {
+		tasks[StepTypeJob][job.Id] = Task{
+			LocationIndex: job.LocationIndex,
+			Setup:         valueOrZero(job.Setup),
+			Service:       valueOrZero(job.Service),
+			TimeWindows:   timeWindowsOrLegacy(job.TimeWindows, job.TimeWindow),
+			Skills:        job.Skills,
+			Priority:      valueOrZero(job.Priority),
+			Unload:        job.Delivery,
+			Load:          job.Pickup,
+		}
+	}
+	for _, shipment := range input.Shipments {
+		if shipment.Pickup == nil || shipment.Delivery == nil {
+			continue
+		}
+		pickup, delivery := shipment.Pickup, shipment.Delivery
+		tasks[StepTypePickup][pickup.Id] = Task{
+			LocationIndex: pickup.LocationIndex,
+			Setup:         valueOrZero(pickup.Setup),
+			Service:       pickup.Service,
+			TimeWindows:   timeWindowsOrLegacy(pickup.TimeWindows, pickup.TimeWindow),
+			Skills:        shipment.Skills,
+			Priority:      valueOrZero(shipment.Priority),
+			Load:          shipment.Amount,
+		}
+		pickupId := pickup.Id
+		tasks[StepTypeDelivery][delivery.Id] = Task{
+			LocationIndex: delivery.LocationIndex,
+			Setup:         valueOrZero(delivery.Setup),
+			Service:       delivery.Service,
+			TimeWindows:   timeWindowsOrLegacy(delivery.TimeWindows, delivery.TimeWindow),
+			Skills:        shipment.Skills,
+			Unload:        shipment.Amount,
+			Pickup:        &pickupId,
+		}
+	}
+	return tasks
+}
+
+// Get returns the task of the given step type and id
+func (t TaskIndex) Get(taskType string, id uint64) (Task, bool) {
+	task, ok := t[taskType][id]
+	return task, ok
+}
+
+// InitialLoad returns the load a vehicle starts with when it serves the given jobs: their deliveries
+func (t TaskIndex) InitialLoad(jobIds []uint64) Load {
+	var load Load
+	for _, id := range jobIds {
+		load = load.add(t[StepTypeJob][id].Unload, 1)
+	}
+	return load
+}
+
+// Load is the multidimensional amount on board of a vehicle
+type Load []int64
+
+// Serve returns the load after task: its unload leaves the vehicle and its load enters it
+func (l Load) Serve(task Task) Load {
+	return l.add(task.Unload, -1).add(task.Load, 1)
+}
+
+// Exceeds reports whether a dimension of l is above capacity. A vehicle without capacity is not
+// limited, while a dimension missing from a given capacity holds nothing.
+func (l Load) Exceeds(capacity []int64) bool {
+	if len(capacity) == 0 {
+		return false
+	}
+	for i, amount := range l {
+		var limit int64
+		if i < len(capacity) {
+			limit = capacity[i]
+		}
+		if amount > limit {
+			return true
+		}
+	}
+	return false
+}
+
+// Values returns the load as reported in HeraldStep.Load
+func (l Load) Values() []float64 {
+	values := make([]float64, len(l))
+	for i, amount := range l {
+		values[i] = float64(amount)
+	}
+	return values
+}
+
+func (l Load) add(amount []uint64, sign int64) Load {
+	for len(l) < len(amount) {
+		l = append(l, 0)
+	}
+	for i, value := range amount {
+		l[i] += sign * int64(value)
+	}
+	return l
+}
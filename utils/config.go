@@ -28,6 +28,7 @@ type Config struct {
 	MDMAreas             map[string]bool    `yaml:"mdm_areas" json:"mdm_areas"`
 	Executor             *ExecutorConf      `yaml:"executor" json:"executor"` // need to change to executor later
 	MassiveConcurrency   int64              `yaml:"massive_concurrency" json:"massive_concurrency"`
+	Validation           *ValidationConf    `yaml:"validation" json:"validation"`
 }
 
 type ExecutorConf struct {
@@ -52,6 +53,24 @@ type MCConsumerConf struct {
 	NumOfFlexLocations   int `yaml:"num_of_flex_locations" json:"num_of_flex_locations"`
 }
 
+// ValidationConf selects the validation rule set of each tenant. A rule set maps a rule id, or the code of
+// a built-in lint, to "error", "warning" or "off".
+type ValidationConf struct {
+	DefaultRuleSet string                       `yaml:"default_rule_set" json:"default_rule_set"`
+	RuleSets       map[string]map[string]string `yaml:"rule_sets" json:"rule_sets"`
+	RuleSetByKey   map[string]string            `yaml:"rule_set_by_key" json:"rule_set_by_key"` // api key -> rule set name
+}
+
+// DefaultMCConsumerConf puts very large numbers to avoid any MDM and on demand executor
+func DefaultMCConsumerConf() *MCConsumerConf {
+	return &MCConsumerConf{
+		NumOfJobs:            5000,
+		NumOfUniqueVehicles:  1000,
+		NumOfNormalLocations: 5000,
+		NumOfFlexLocations:   5000,
+	}
+}
+
 func (c *RedisFailOverConf) GetSentinelAddress() []string {
 	logrus.Infof("prefix: %s, name: %s, sentinel port: %s", c.Prefix, c.Name, c.SentinelPort)
 	addr := c.Prefix + c.Name + ":" + c.SentinelPort
@@ -100,14 +119,7 @@ func Init() {
 	}
 
 	if Conf.MCConsumer == nil {
-		// Put very large numbers to avoid any MDM and on demand executor
-		var mcConsumer = MCConsumerConf{
-			NumOfJobs:            5000,
-			NumOfUniqueVehicles:  1000,
-			NumOfNormalLocations: 5000,
-			NumOfFlexLocations:   5000,
-		}
-		Conf.MCConsumer = &mcConsumer
+		Conf.MCConsumer = DefaultMCConsumerConf()
 	}
 
 	if Conf.Executor == nil {
@@ -128,6 +140,21 @@ func validateConf() {
 	if Conf.RedisHost == "" && Conf.RedisRFSHost == nil {
 		panic("empty RedisHost")
 	}
+	if Conf.Validation != nil {
+		for name, ruleSet := range Conf.Validation.RuleSets {
+			for rule, severity := range ruleSet {
+				if severity != "error" && severity != "warning" && severity != "off" {
+					panic("invalid severity " + severity + " for rule " + rule + " in validation rule set " + name)
+				}
+			}
+		}
+		// keys are not logged, they are credentials
+		for _, name := range Conf.Validation.RuleSetByKey {
+			if _, ok := Conf.Validation.RuleSets[name]; !ok {
+				panic("unknown validation rule set " + name + " in rule_set_by_key")
+			}
+		}
+	}
 	/*
 		if Conf.GatewayHost == "" {
 			panic("empty GatewayHost")
@@ -0,0 +1,151 @@
+package validations
+
+import (
+	"fmt"
+
+	structs "github.com/nextbillion-ai/nb-optimization-interface/structs"
+)
+
+// validateCapacities checks that every capacity and amount has the same number of dimensions
+// and warns about tasks that no vehicle can ever carry.
+func validateCapacities(c *collector, input *structs.OptimizationPostInput) bool {
+	dimensions, source := capacityDimensions(input)
+	if dimensions == 0 {
+		return c.more()
+	}
+	check := func(path string, length int) bool {
+		if length == 0 || length == dimensions {
+			return c.more()
+		}
+		return c.add(newError(path, CodeCapacityDimensionMismatch, "%d capacity dimension(s) given while %s has %d. Please ensure that capacity, delivery, pickup and amount all have the same number of dimensions", length, source, dimensions))
+	}
+
+	consistent := true
+	for i, vehicle := range input.Vehicles {
+		if !check(fmt.Sprintf("vehicles[%d].capacity", i), len(vehicle.Capacity)) {
+			return false
+		}
+		consistent = consistent && (len(vehicle.Capacity) == 0 || len(vehicle.Capacity) == dimensions)
+		for d, value := range vehicle.Capacity {
+			if value < 0 {
+				if !c.add(newError(fmt.Sprintf("vehicles[%d].capacity[%d]", i, d), CodeNegativeCapacity, "invalid capacity %d for vehicle %d. Please provide a capacity that is not negative", value, vehicle.Id)) {
+					return false
+				}
+			}
+		}
+	}
+	for i, job := range input.Jobs {
+		if !check(fmt.Sprintf("jobs[%d].delivery", i), len(job.Delivery)) || !check(fmt.Sprintf("jobs[%d].pickup", i), len(job.Pickup)) {
+			return false
+		}
+		consistent = consistent && (len(job.Delivery) == 0 || len(job.Delivery) == dimensions) && (len(job.Pickup) == 0 || len(job.Pickup) == dimensions)
+	}
+	for i, shipment := range input.Shipments {
+		if !check(fmt.Sprintf("shipments[%d].amount", i), len(shipment.Amount)) {
+			return false
+		}
+		consistent = consistent && (len(shipment.Amount) == 0 || len(shipment.Amount) == dimensions)
+	}
+
+	// fitting is only meaningful once the dimensions line up
+	if !consistent {
+		return c.more()
+	}
+	return validateTaskFits(c, input)
+}
+
+// capacityDimensions returns the dimension count of the first capacity or amount given and where it was found
+func capacityDimensions(input *structs.OptimizationPostInput) (int, string) {
+	for i, vehicle := range input.Vehicles {
+		if len(vehicle.Capacity) > 0 {
+			return len(vehicle.Capacity), fmt.Sprintf("vehicles[%d].capacity", i)
+		}
+	}
+	for i, job := range input.Jobs {
+		if len(job.Delivery) > 0 {
+			return len(job.Delivery), fmt.Sprintf("jobs[%d].delivery", i)
+		}
+		if len(job.Pickup) > 0 {
+			return len(job.Pickup), fmt.Sprintf("jobs[%d].pickup", i)
+		}
+	}
+	for i, shipment := range input.Shipments {
+		if len(shipment.Amount) > 0 {
+			return len(shipment.Amount), fmt.Sprintf("shipments[%d].amount", i)
+		}
+	}
+	return 0, ""
+}
+
+func validateTaskFits(c *collector, input *structs.OptimizationPostInput) bool {
+	if len(input.Vehicles) == 0 {
+		return c.more()
+	}
+	capacities := make([][]int64, 0, len(input.Vehicles))
+	for _, vehicle := range input.Vehicles {
+		// a vehicle without capacity is not limited, so every task fits
+		if len(vehicle.Capacity) == 0 {
+			return c.more()
+		}
+		capacities = append(capacities, vehicle.Capacity)
+	}
+
+	for i, job := range input.Jobs {
+		if !validateTaskFit(c, fmt.Sprintf("jobs[%d].delivery", i), fmt.Sprintf("job %d", job.Id), job.Delivery, capacities) ||
+			!validateTaskFit(c, fmt.Sprintf("jobs[%d].pickup", i), fmt.Sprintf("job %d", job.Id), job.Pickup, capacities) {
+			return false
+		}
+	}
+	for i, shipment := range input.Shipments {
+		task := fmt.Sprintf("shipment at index %d", i)
+		if shipment.Pickup != nil {
+			task = fmt.Sprintf("shipment with pickup %d", shipment.Pickup.Id)
+		}
+		if !validateTaskFit(c, fmt.Sprintf("shipments[%d].amount", i), task, shipment.Amount, capacities) {
+			return false
+		}
+	}
+	return true
+}
+
+// validateTaskFit warns when amount exceeds the capacity of every vehicle
+func validateTaskFit(c *collector, path string, task string, amount []uint64, capacities [][]int64) bool {
+	if len(amount) == 0 {
+		return c.more()
+	}
+	var largest []int64
+	for _, capacity := range capacities {
+		if fits(amount, capacity) {
+			return c.more()
+		}
+		if largest == nil {
+			largest = append([]int64(nil), capacity...)
+		}
+		for d, value := range capacity {
+			largest[d] = max(largest[d], value)
+		}
+	}
+
+	reported := false
+	for d, value := range amount {
+		if value > uint64(max(largest[d], 0)) {
+			reported = true
+			if !c.add(newWarning(fmt.Sprintf("%s[%d]", path, d), CodeTaskExceedsCapacity, "%s needs %d in capacity dimension %d but no vehicle can carry more than %d, so it will never be assigned", task, value, d, largest[d])) {
+				return false
+			}
+		}
+	}
+	if !reported {
+		return c.add(newWarning(path, CodeTaskExceedsCapacity, "%s does not fit in any single vehicle across all capacity dimensions at once, so it will never be assigned", task))
+	}
+	return c.more()
+}
+
+func fits(amount []uint64, capacity []int64) bool {
+	for d, value := range amount {
+		if capacity[d] < 0 || value > uint64(capacity[d]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,63 @@
+package validations
+
+import (
+	"fmt"
+	"slices"
+
+	structs "github.com/nextbillion-ai/nb-optimization-interface/structs"
+)
+
+// ResolveDepots fills, in place, the start and end of every vehicle that omits them from its depot.
+// A vehicle with a list of allowed depots keeps its start and end open so that the engine chooses
+// among them, unless the list has a single depot. An explicit start or end that differs from the
+// depot location is kept and reported as a conflict. Depot ids are expected to be declared, which
+// Validate checks first.
+func ResolveDepots(input *structs.OptimizationPostInput) (structs.ValidationErrors, error) {
+	c := newCollector(ValidateOptions{CollectAll: true})
+	resolveDepots(c, input)
+	return c.warnings, c.err()
+}
+
+func resolveDepots(c *collector, input *structs.OptimizationPostInput) bool {
+	locations := make(map[uint64]uint64, len(input.Depots)+len(input.Depot))
+	for _, depot := range append(slices.Clone(input.Depot), input.Depots...) {
+		locations[depot.Id] = depot.LocationIndex
+	}
+
+	for i := range input.Vehicles {
+		vehicle := &input.Vehicles[i]
+		path := fmt.Sprintf("vehicles[%d]", i)
+
+		depot := vehicle.Depot
+		if depot != nil && len(vehicle.Depots) > 0 && !slices.Contains(vehicle.Depots, *depot) {
+			if !c.add(newError(path+".depot", CodeDepotConflict, "depot %d of vehicle %d is not one of its allowed depots %v. Please ensure that \"depot\" belongs to \"depots\"", *depot, vehicle.Id, vehicle.Depots)) {
+				return false
+			}
+			continue
+		}
+		if depot == nil && len(vehicle.Depots) == 1 {
+			depot = &vehicle.Depots[0]
+		}
+		if depot == nil {
+			continue
+		}
+		locationIndex, ok := locations[*depot]
+		if !ok {
+			continue
+		}
+
+		if vehicle.StartIndex == nil {
+			start := locationIndex
+			vehicle.StartIndex = &start
+		} else if *vehicle.StartIndex != locationIndex && !c.add(newWarning(path+".start_index", CodeDepotConflict, "start_index %d of vehicle %d differs from the location %d of its depot %d, start_index is kept", *vehicle.StartIndex, vehicle.Id, locationIndex, *depot)) {
+			return false
+		}
+		if vehicle.EndIndex == nil {
+			end := locationIndex
+			vehicle.EndIndex = &end
+		} else if *vehicle.EndIndex != locationIndex && !c.add(newWarning(path+".end_index", CodeDepotConflict, "end_index %d of vehicle %d differs from the location %d of its depot %d, end_index is kept", *vehicle.EndIndex, vehicle.Id, locationIndex, *depot)) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,123 @@
+package validations
+
+import (
+	"errors"
+	"fmt"
+
+	structs "github.com/nextbillion-ai/nb-optimization-interface/structs"
+)
+
+// Stable codes reported in structs.ValidationError. Clients match on these, so never rename one.
+const (
+	CodeInvalidLocation        = "invalid_location"
+	CodeApproachCountMismatch  = "approach_count_mismatch"
+	CodeInvalidApproach        = "invalid_approach"
+	CodeInvalidTravelCost      = "invalid_travel_cost"
+	CodeInvalidCostMatrix      = "invalid_cost_matrix"
+	CodeInvalidTruckSize       = structs.CodeInvalidTruckSize
+	CodeIgnoredOption          = "ignored_option"
+	CodeInvalidTimeWindow      = "invalid_time_window"
+	CodeTimestampOutOfRange    = "timestamp_out_of_range"
+	CodeOverlappingTimeWindows = "overlapping_time_windows"
+	CodeMissingShipmentStep    = "missing_shipment_step"
+	CodeMissingVehicle         = "missing_vehicle"
+	CodeMissingBreakTimeWindow = "missing_break_time_window"
+
+	// referential integrity
+	CodeLocationIndexOutOfRange = "location_index_out_of_range"
+	CodeUnknownDepot            = "unknown_depot"
+	CodeDuplicateId             = "duplicate_id"
+
+	// coordinates, the parser lives in structs
+	CodeInvalidCoordinate = structs.CodeInvalidCoordinate
+
+	// capacities
+	CodeCapacityDimensionMismatch = "capacity_dimension_mismatch"
+	CodeNegativeCapacity          = "negative_capacity"
+	CodeTaskExceedsCapacity       = "task_exceeds_capacity"
+
+	// skills
+	CodeMissingSkill = "missing_skill"
+
+	// time window feasibility
+	CodeLongTimeWindow       = "long_time_window"
+	CodeOutsideVehicleShifts = "outside_vehicle_shifts"
+	CodeBreakOutsideShift    = "break_outside_shift"
+	CodeDeliveryBeforePickup = "delivery_before_pickup"
+
+	// pinned vehicle steps
+	CodeInvalidStepType    = "invalid_step_type"
+	CodeUnknownStepId      = "unknown_step_id"
+	CodeInvalidServiceTime = "invalid_service_time"
+	CodeTaskPinnedTwice    = "task_pinned_twice"
+
+	// legacy fields
+	CodeDeprecatedField   = "deprecated_field"
+	CodeConflictingFields = "conflicting_fields"
+
+	// value ranges
+	CodePriorityOutOfRange = "priority_out_of_range"
+	CodeDurationOutOfRange = "duration_out_of_range"
+	CodeLongDuration       = "long_duration"
+
+	// routing options, the truck profile parser lives in structs
+	CodeInvalidMode           = structs.CodeInvalidMode
+	CodeInvalidTruckWeight    = structs.CodeInvalidTruckWeight
+	CodeInvalidTruckAxleCount = structs.CodeInvalidTruckAxleCount
+
+	// customized cost matrix
+	CodeNonZeroDiagonal      = "non_zero_diagonal"
+	CodeUnreachableLocation  = "unreachable_location"
+	CodeAsymmetricCostMatrix = "asymmetric_cost_matrix"
+	CodeTriangleInequality   = "triangle_inequality"
+	CodeCostOverflow         = "cost_overflow"
+
+	// depot resolution
+	CodeDepotConflict = "depot_conflict"
+
+	// solution verification
+	CodeUnknownVehicle   = "unknown_vehicle"
+	CodeVehicleUsedTwice = "vehicle_used_twice"
+	CodeTaskServedTwice  = "task_served_twice"
+	CodeShipmentOrder    = "shipment_order"
+	CodeCapacityExceeded = "capacity_exceeded"
+	CodeTimeWindowMissed = "time_window_missed"
+	CodeSkillMismatch    = "skill_mismatch"
+	CodeMaxTasksExceeded = "max_tasks_exceeded"
+
+	// profile matrices, the check lives in structs
+	CodeMissingMatrix      = structs.CodeMissingMatrix
+	CodeMatrixSizeMismatch = structs.CodeMatrixSizeMismatch
+)
+
+func newError(path string, code string, format string, args ...any) structs.ValidationError {
+	return structs.ValidationError{
+		Path:     path,
+		Code:     code,
+		Severity: structs.SeverityError,
+		Message:  fmt.Sprintf(format, args...),
+	}
+}
+
+func newWarning(path string, code string, format string, args ...any) structs.ValidationError {
+	return structs.ValidationError{
+		Path:     path,
+		Code:     code,
+		Severity: structs.SeverityWarning,
+		Message:  fmt.Sprintf(format, args...),
+	}
+}
+
+// toValidationErrors turns any error returned by a validator into the structured list.
+// Errors that carry no structure are reported under the given path and code.
+func toValidationErrors(err error, path string, code string) structs.ValidationErrors {
+	var list structs.ValidationErrors
+	if errors.As(err, &list) {
+		return list
+	}
+	var single structs.ValidationError
+	if errors.As(err, &single) {
+		return structs.ValidationErrors{single}
+	}
+	return structs.ValidationErrors{newError(path, code, "%s", err.Error())}
+}
@@ -0,0 +1,121 @@
+package validations
+
+import (
+	"fmt"
+	"math/bits"
+
+	structs "github.com/nextbillion-ai/nb-optimization-interface/structs"
+)
+
+const (
+	// costs at or above this value mean the destination can not be reached
+	unreachableCost = maxEngineValue
+	// a direct cost above this ratio of a detour through another location is reported
+	triangleViolationRatio = 2.0
+	// the triangle check is cubic, larger matrices are skipped
+	maxTriangleCheckSize = 200
+	// a relative difference between both directions above this ratio is reported
+	asymmetryRatio = 0.5
+)
+
+// validateCostMatrixQuality looks for the mistakes usually made when a customized cost matrix is pasted
+// from a spreadsheet. Its size has already been checked by validateCostMatrix.
+func validateCostMatrixQuality(c *collector, input *structs.OptimizationPostInput) bool {
+	if input.Options.Objective.TravelCost != "customized" {
+		return c.more()
+	}
+	matrix := input.CostMatrix
+	if validateCostMatrix(locationCount(input.Locations), matrix) != nil {
+		return c.more()
+	}
+
+	for i := range matrix {
+		if matrix[i][i] != 0 && !c.add(newError(fmt.Sprintf("cost_matrix[%d][%d]", i, i), CodeNonZeroDiagonal, "the cost from location %d to itself is %d. Please ensure that the diagonal of the cost matrix is 0", i, matrix[i][i])) {
+			return false
+		}
+	}
+	if len(matrix) > 1 {
+		for i, row := range matrix {
+			reachable := false
+			for j, value := range row {
+				reachable = reachable || (i != j && value < unreachableCost)
+			}
+			if !reachable && !c.add(newError(fmt.Sprintf("cost_matrix[%d]", i), CodeUnreachableLocation, "location %d can not reach any other location, all of its costs are %d or more. Please ensure that the cost matrix is correct", i, unreachableCost)) {
+				return false
+			}
+		}
+	}
+
+	return checkAsymmetry(c, matrix) && checkTriangleInequality(c, matrix) && checkCostOverflow(c, matrix)
+}
+
+func checkAsymmetry(c *collector, matrix [][]uint64) bool {
+	count := 0
+	var first string
+	for i := range matrix {
+		for j := i + 1; j < len(matrix); j++ {
+			a, b := matrix[i][j], matrix[j][i]
+			if a >= unreachableCost || b >= unreachableCost {
+				continue
+			}
+			low, high := min(a, b), max(a, b)
+			if float64(high-low) > asymmetryRatio*float64(high) {
+				if count == 0 {
+					first = fmt.Sprintf("%d from location %d to %d but %d back", a, i, j, b)
+				}
+				count++
+			}
+		}
+	}
+	if count == 0 {
+		return c.more()
+	}
+	return c.add(newWarning("cost_matrix", CodeAsymmetricCostMatrix, "%d pair(s) of locations have costs that differ by more than %v%% between both directions, e.g. %s. Please ensure that rows and columns are not swapped", count, asymmetryRatio*100, first))
+}
+
+func checkTriangleInequality(c *collector, matrix [][]uint64) bool {
+	if len(matrix) > maxTriangleCheckSize {
+		return c.more()
+	}
+	count := 0
+	var first string
+	for i := range matrix {
+		for k := range matrix {
+			direct := matrix[i][k]
+			if i == k || direct >= unreachableCost {
+				continue
+			}
+			for j := range matrix {
+				if j == i || j == k || matrix[i][j] >= unreachableCost || matrix[j][k] >= unreachableCost {
+					continue
+				}
+				if float64(direct) > triangleViolationRatio*float64(matrix[i][j]+matrix[j][k]) {
+					if count == 0 {
+						first = fmt.Sprintf("%d from location %d to %d but %d via location %d", direct, i, k, matrix[i][j]+matrix[j][k], j)
+					}
+					count++
+					break
+				}
+			}
+		}
+	}
+	if count == 0 {
+		return c.more()
+	}
+	return c.add(newWarning("cost_matrix", CodeTriangleInequality, "%d direct cost(s) are more than %v times the cost of a detour through another location, e.g. %s", count, triangleViolationRatio, first))
+}
+
+// checkCostOverflow warns when adding up the costs, as the engine does along a route, can overflow uint64.
+// Unreachable costs are included, the engine adds them like any other cost.
+func checkCostOverflow(c *collector, matrix [][]uint64) bool {
+	var sum, carry uint64
+	for _, row := range matrix {
+		for _, value := range row {
+			sum, carry = bits.Add64(sum, value, 0)
+			if carry != 0 {
+				return c.add(newWarning("cost_matrix", CodeCostOverflow, "the costs in the cost matrix are so large that their sum overflows. Please scale the costs down and use %d for unreachable locations", unreachableCost))
+			}
+		}
+	}
+	return c.more()
+}
@@ -0,0 +1,116 @@
+package validations
+
+import (
+	"fmt"
+	"reflect"
+
+	structs "github.com/nextbillion-ai/nb-optimization-interface/structs"
+)
+
+// Normalize merges the legacy duplicates of the contract into their current field, in place:
+//
+//	jobs[].time_window            -> jobs[].time_windows
+//	shipments[].*.time_window     -> shipments[].*.time_windows
+//	depot                         -> depots
+//	mode                          -> options.routing.mode
+//	vehicles[].costs.per_hours    -> vehicles[].costs.per_hour
+//
+// The current field always wins. Using a legacy field is reported as a deprecation warning, and giving
+// both with different values as a conflict. The top level mode is kept in sync for older readers.
+func Normalize(input *structs.OptimizationPostInput) structs.ValidationErrors {
+	c := newCollector(ValidateOptions{CollectAll: true})
+	normalize(c, input)
+	return c.warnings
+}
+
+func normalize(c *collector, input *structs.OptimizationPostInput) bool {
+	for i := range input.Jobs {
+		job := &input.Jobs[i]
+		if !mergeTimeWindows(c, fmt.Sprintf("jobs[%d]", i), &job.TimeWindows, &job.TimeWindow) {
+			return false
+		}
+	}
+	for i := range input.Shipments {
+		shipment := &input.Shipments[i]
+		if shipment.Pickup != nil && !mergeTimeWindows(c, fmt.Sprintf("shipments[%d].pickup", i), &shipment.Pickup.TimeWindows, &shipment.Pickup.TimeWindow) {
+			return false
+		}
+		if shipment.Delivery != nil && !mergeTimeWindows(c, fmt.Sprintf("shipments[%d].delivery", i), &shipment.Delivery.TimeWindows, &shipment.Delivery.TimeWindow) {
+			return false
+		}
+	}
+
+	if len(input.Depot) > 0 {
+		if !c.add(deprecated("", "depot", "depots")) {
+			return false
+		}
+		if len(input.Depots) == 0 {
+			input.Depots = input.Depot
+		} else if !reflect.DeepEqual(input.Depots, input.Depot) && !c.add(conflict("", "depot", "depots")) {
+			return false
+		}
+		input.Depot = nil
+	}
+
+	routing := &input.Options.Routing
+	if input.Mode != nil && len(*input.Mode) > 0 {
+		if routing.Mode == nil || len(*routing.Mode) == 0 {
+			if !c.add(deprecated("", "mode", "options.routing.mode")) {
+				return false
+			}
+			routing.Mode = input.Mode
+		} else if *routing.Mode != *input.Mode && !c.add(conflict("", "mode", "options.routing.mode")) {
+			return false
+		}
+	}
+	input.Mode = routing.Mode
+
+	for i := range input.Vehicles {
+		costs := &input.Vehicles[i].Costs
+		if costs.PerHours == nil {
+			continue
+		}
+		path := fmt.Sprintf("vehicles[%d].costs", i)
+		if !c.add(deprecated(path, "per_hours", "per_hour")) {
+			return false
+		}
+		if costs.PerHour == nil {
+			costs.PerHour = costs.PerHours
+		} else if *costs.PerHour != *costs.PerHours && !c.add(conflict(path, "per_hours", "per_hour")) {
+			return false
+		}
+		costs.PerHours = nil
+	}
+	return true
+}
+
+func mergeTimeWindows(c *collector, path string, timeWindows *[][]uint64, legacy *[][]uint64) bool {
+	if len(*legacy) == 0 {
+		return c.more()
+	}
+	if !c.add(deprecated(path, "time_window", "time_windows")) {
+		return false
+	}
+	if len(*timeWindows) == 0 {
+		*timeWindows = *legacy
+	} else if !reflect.DeepEqual(*timeWindows, *legacy) && !c.add(conflict(path, "time_window", "time_windows")) {
+		return false
+	}
+	*legacy = nil
+	return c.more()
+}
+
+func deprecated(parent string, legacy string, current string) structs.ValidationError {
+	return newWarning(fieldPath(parent, legacy), CodeDeprecatedField, "%s is deprecated, please use %s instead", legacy, current)
+}
+
+func conflict(parent string, legacy string, current string) structs.ValidationError {
+	return newWarning(fieldPath(parent, legacy), CodeConflictingFields, "both %s and %s are given with different values, %s is ignored", legacy, current, legacy)
+}
+
+func fieldPath(parent string, field string) string {
+	if len(parent) == 0 {
+		return field
+	}
+	return parent + "." + field
+}
@@ -0,0 +1,79 @@
+package validations
+
+import (
+	"fmt"
+
+	structs "github.com/nextbillion-ai/nb-optimization-interface/structs"
+)
+
+const (
+	maxPriority uint64 = 100
+	// the engine keeps timestamps and durations in 32 bits
+	maxEngineValue uint64 = 4294967295
+	// durations above this are accepted but almost always a unit mistake
+	longDuration uint64 = 24 * 60 * 60
+)
+
+// validateRanges checks the priorities and the service and setup durations of every task and break
+func validateRanges(c *collector, input *structs.OptimizationPostInput) bool {
+	for i, job := range input.Jobs {
+		path := fmt.Sprintf("jobs[%d]", i)
+		task := fmt.Sprintf("job %d", job.Id)
+		if !checkPriority(c, path+".priority", task, job.Priority) ||
+			!checkDuration(c, path+".service", task, "service", job.Service) ||
+			!checkDuration(c, path+".setup", task, "setup", job.Setup) {
+			return false
+		}
+	}
+	for i, shipment := range input.Shipments {
+		if !checkPriority(c, fmt.Sprintf("shipments[%d].priority", i), fmt.Sprintf("shipment at index %d", i), shipment.Priority) {
+			return false
+		}
+		steps := []struct {
+			kind string
+			step *structs.ShipmentStep
+		}{{"pickup", shipment.Pickup}, {"delivery", shipment.Delivery}}
+		for _, item := range steps {
+			step := item.step
+			if step == nil {
+				continue
+			}
+			path := fmt.Sprintf("shipments[%d].%s", i, item.kind)
+			task := fmt.Sprintf("shipment %s %d", item.kind, step.Id)
+			service := step.Service
+			if !checkDuration(c, path+".service", task, "service", &service) ||
+				!checkDuration(c, path+".setup", task, "setup", step.Setup) {
+				return false
+			}
+		}
+	}
+	for i, vehicle := range input.Vehicles {
+		for j, b := range vehicle.Breaks {
+			service := b.Service
+			if !checkDuration(c, fmt.Sprintf("vehicles[%d].breaks[%d].service", i, j), fmt.Sprintf("break %d of vehicle %d", b.Id, vehicle.Id), "service", &service) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func checkPriority(c *collector, path string, task string, priority *uint64) bool {
+	if priority == nil || *priority <= maxPriority {
+		return c.more()
+	}
+	return c.add(newError(path, CodePriorityOutOfRange, "invalid priority %d for %s. Please provide a priority in the range of [0, %d]", *priority, task, maxPriority))
+}
+
+func checkDuration(c *collector, path string, task string, field string, duration *uint64) bool {
+	if duration == nil {
+		return c.more()
+	}
+	if *duration > maxEngineValue {
+		return c.add(newError(path, CodeDurationOutOfRange, "invalid %s duration %d for %s. Please provide a duration less than %d seconds", field, *duration, task, maxEngineValue))
+	}
+	if *duration > longDuration {
+		return c.add(newWarning(path, CodeLongDuration, "the %s duration of %s is longer than 24 hours. Please ensure that it is given in seconds", field, task))
+	}
+	return c.more()
+}
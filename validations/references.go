@@ -0,0 +1,146 @@
+package validations
+
+import (
+	"fmt"
+	"strings"
+
+	structs "github.com/nextbillion-ai/nb-optimization-interface/structs"
+)
+
+func locationCount(locations structs.Locations) int {
+	return len(strings.Split(locations.Location, "|"))
+}
+
+// validateReferences checks that every location index points into the location list, that
+// vehicles only reference declared depots and that ids are not duplicated.
+func validateReferences(c *collector, input *structs.OptimizationPostInput) bool {
+	return validateLocationIndices(c, input) &&
+		validateDepotReferences(c, input) &&
+		validateUniqueIds(c, input)
+}
+
+func validateLocationIndices(c *collector, input *structs.OptimizationPostInput) bool {
+	length := uint64(locationCount(input.Locations))
+	check := func(path string, index uint64) bool {
+		if index < length {
+			return c.more()
+		}
+		return c.add(newError(path, CodeLocationIndexOutOfRange, "invalid location index %d. The valid value range is [0, %d), please ensure that it refers to one of the provided locations", index, length))
+	}
+
+	for i, job := range input.Jobs {
+		if !check(fmt.Sprintf("jobs[%d].location_index", i), job.LocationIndex) {
+			return false
+		}
+	}
+	for i, shipment := range input.Shipments {
+		if shipment.Pickup != nil && !check(fmt.Sprintf("shipments[%d].pickup.location_index", i), shipment.Pickup.LocationIndex) {
+			return false
+		}
+		if shipment.Delivery != nil && !check(fmt.Sprintf("shipments[%d].delivery.location_index", i), shipment.Delivery.LocationIndex) {
+			return false
+		}
+	}
+	for i, vehicle := range input.Vehicles {
+		if vehicle.StartIndex != nil && !check(fmt.Sprintf("vehicles[%d].start_index", i), *vehicle.StartIndex) {
+			return false
+		}
+		if vehicle.EndIndex != nil && !check(fmt.Sprintf("vehicles[%d].end_index", i), *vehicle.EndIndex) {
+			return false
+		}
+	}
+	for i, depot := range input.Depots {
+		if !check(fmt.Sprintf("depots[%d].location_index", i), depot.LocationIndex) {
+			return false
+		}
+	}
+	for i, depot := range input.Depot {
+		if !check(fmt.Sprintf("depot[%d].location_index", i), depot.LocationIndex) {
+			return false
+		}
+	}
+	return true
+}
+
+func validateDepotReferences(c *collector, input *structs.OptimizationPostInput) bool {
+	depots := make(map[uint64]bool, len(input.Depots)+len(input.Depot))
+	for _, depot := range input.Depots {
+		depots[depot.Id] = true
+	}
+	for _, depot := range input.Depot {
+		depots[depot.Id] = true
+	}
+	for i, vehicle := range input.Vehicles {
+		if vehicle.Depot != nil && !depots[*vehicle.Depot] {
+			if !c.add(newError(fmt.Sprintf("vehicles[%d].depot", i), CodeUnknownDepot, "vehicle %d refers to depot %d which is not declared. Please ensure that the depot is provided in \"depots\"", vehicle.Id, *vehicle.Depot)) {
+				return false
+			}
+		}
+		for j, depot := range vehicle.Depots {
+			if !depots[depot] && !c.add(newError(fmt.Sprintf("vehicles[%d].depots[%d]", i, j), CodeUnknownDepot, "vehicle %d refers to depot %d which is not declared. Please ensure that the depot is provided in \"depots\"", vehicle.Id, depot)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func validateUniqueIds(c *collector, input *structs.OptimizationPostInput) bool {
+	jobs := newIdSet("job", "")
+	for i, job := range input.Jobs {
+		if !jobs.add(c, fmt.Sprintf("jobs[%d]", i), job.Id) {
+			return false
+		}
+	}
+
+	pickups := newIdSet("shipment pickup", "")
+	deliveries := newIdSet("shipment delivery", "")
+	for i, shipment := range input.Shipments {
+		if shipment.Pickup != nil && !pickups.add(c, fmt.Sprintf("shipments[%d].pickup", i), shipment.Pickup.Id) {
+			return false
+		}
+		if shipment.Delivery != nil && !deliveries.add(c, fmt.Sprintf("shipments[%d].delivery", i), shipment.Delivery.Id) {
+			return false
+		}
+	}
+
+	vehicles := newIdSet("vehicle", "")
+	for i, vehicle := range input.Vehicles {
+		if !vehicles.add(c, fmt.Sprintf("vehicles[%d]", i), vehicle.Id) {
+			return false
+		}
+		breaks := newIdSet("break", fmt.Sprintf(" of vehicle %d", vehicle.Id))
+		for j, b := range vehicle.Breaks {
+			if !breaks.add(c, fmt.Sprintf("vehicles[%d].breaks[%d]", i, j), b.Id) {
+				return false
+			}
+		}
+	}
+
+	depots := newIdSet("depot", "")
+	for i, depot := range input.Depots {
+		if !depots.add(c, fmt.Sprintf("depots[%d]", i), depot.Id) {
+			return false
+		}
+	}
+	return true
+}
+
+// idSet remembers where each id was first seen so duplicates can name both places
+type idSet struct {
+	kind  string
+	scope string
+	seen  map[uint64]string
+}
+
+func newIdSet(kind string, scope string) *idSet {
+	return &idSet{kind: kind, scope: scope, seen: map[uint64]string{}}
+}
+
+func (s *idSet) add(c *collector, path string, id uint64) bool {
+	if first, ok := s.seen[id]; ok {
+		return c.add(newError(path+".id", CodeDuplicateId, "duplicate %s id %d%s, already used by %s. Please ensure that every %s%s has a unique id", s.kind, id, s.scope, first, s.kind, s.scope))
+	}
+	s.seen[id] = path
+	return c.more()
+}
@@ -0,0 +1,131 @@
+package validations
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	structs "github.com/nextbillion-ai/nb-optimization-interface/structs"
+)
+
+type Change struct {
+	Path    string `json:"path"`    // Describe the JSON path of the repaired field
+	Message string `json:"message"` // Describe what has been changed
+}
+
+// Repair fixes the mechanical mistakes that make most uploads fail, in place, and returns every change made:
+//
+//   - time windows that are unsorted or touch each other are sorted and merged
+//   - approaches are trimmed and lowercased when that makes them valid, "Curb" becomes "curb"
+//   - priorities above 100 are lowered to 100
+//   - a time window given in the legacy time_window field is moved to time_windows
+//
+// Ambiguous cases such as overlapping time windows, or time_window and time_windows both given, are left
+// untouched so that Validate still reports them. Repair is opt-in, call it before Validate.
+func Repair(input *structs.OptimizationPostInput) []Change {
+	var changes []Change
+
+	for i, approach := range input.Locations.Approaches {
+		repaired := strings.ToLower(strings.TrimSpace(approach))
+		if repaired != approach && (repaired == "curb" || repaired == "unrestricted" || repaired == "") {
+			input.Locations.Approaches[i] = repaired
+			changes = append(changes, Change{fmt.Sprintf("locations.approaches[%d]", i), fmt.Sprintf("approach %q changed to %q", approach, repaired)})
+		}
+	}
+
+	for i := range input.Jobs {
+		job := &input.Jobs[i]
+		path := fmt.Sprintf("jobs[%d]", i)
+		changes = append(changes, repairLegacyTimeWindow(path, &job.TimeWindows, &job.TimeWindow)...)
+		changes = append(changes, repairTimeWindows(path+".time_windows", &job.TimeWindows)...)
+		changes = append(changes, repairPriority(path+".priority", job.Priority)...)
+	}
+	for i := range input.Shipments {
+		shipment := &input.Shipments[i]
+		steps := []struct {
+			kind string
+			step *structs.ShipmentStep
+		}{{"pickup", shipment.Pickup}, {"delivery", shipment.Delivery}}
+		for _, item := range steps {
+			step := item.step
+			if step == nil {
+				continue
+			}
+			path := fmt.Sprintf("shipments[%d].%s", i, item.kind)
+			changes = append(changes, repairLegacyTimeWindow(path, &step.TimeWindows, &step.TimeWindow)...)
+			changes = append(changes, repairTimeWindows(path+".time_windows", &step.TimeWindows)...)
+		}
+		changes = append(changes, repairPriority(fmt.Sprintf("shipments[%d].priority", i), shipment.Priority)...)
+	}
+	for i := range input.Vehicles {
+		for j := range input.Vehicles[i].Breaks {
+			b := &input.Vehicles[i].Breaks[j]
+			changes = append(changes, repairTimeWindows(fmt.Sprintf("vehicles[%d].breaks[%d].time_windows", i, j), &b.TimeWindows)...)
+		}
+	}
+	return changes
+}
+
+func repairLegacyTimeWindow(path string, timeWindows *[][]uint64, legacy *[][]uint64) []Change {
+	if len(*legacy) == 0 || len(*timeWindows) > 0 {
+		return nil
+	}
+	*timeWindows = *legacy
+	*legacy = nil
+	return []Change{{path + ".time_window", "time_window moved to time_windows"}}
+}
+
+// repairTimeWindows sorts the time windows and merges the ones that touch. Lists with a malformed
+// or overlapping window are left as they are.
+func repairTimeWindows(path string, timeWindows *[][]uint64) []Change {
+	if len(*timeWindows) < 2 {
+		return nil
+	}
+	for _, timeWindow := range *timeWindows {
+		if len(timeWindow) != 2 || timeWindow[0] >= timeWindow[1] {
+			return nil
+		}
+	}
+
+	var changes []Change
+	sorted := slices.Clone(*timeWindows)
+	slices.SortStableFunc(sorted, func(a, b []uint64) int {
+		if a[0] < b[0] {
+			return -1
+		}
+		if a[0] > b[0] {
+			return 1
+		}
+		return 0
+	})
+	if !slices.EqualFunc(sorted, *timeWindows, slices.Equal) {
+		changes = append(changes, Change{path, "time windows sorted from earliest to latest"})
+	}
+
+	merged := [][]uint64{slices.Clone(sorted[0])}
+	for _, timeWindow := range sorted[1:] {
+		last := merged[len(merged)-1]
+		if timeWindow[0] < last[1] {
+			return nil
+		}
+		if timeWindow[0] == last[1] {
+			changes = append(changes, Change{path, fmt.Sprintf("time windows [%d, %d] and [%d, %d] merged as they touch", last[0], last[1], timeWindow[0], timeWindow[1])})
+			last[1] = timeWindow[1]
+			continue
+		}
+		merged = append(merged, slices.Clone(timeWindow))
+	}
+	if len(changes) > 0 {
+		*timeWindows = merged
+	}
+	return changes
+}
+
+func repairPriority(path string, priority *uint64) []Change {
+	if priority == nil || *priority <= maxPriority {
+		return nil
+	}
+	change := Change{path, fmt.Sprintf("priority %d lowered to %d", *priority, maxPriority)}
+	*priority = maxPriority
+	return []Change{change}
+}
@@ -0,0 +1,166 @@
+package validations
+
+import (
+	"fmt"
+	"sync"
+
+	structs "github.com/nextbillion-ai/nb-optimization-interface/structs"
+	"github.com/nextbillion-ai/nb-optimization-interface/utils"
+)
+
+// SeverityOff disables a rule or a built-in lint in a rule set
+const SeverityOff structs.Severity = "off"
+
+// Ids of the rules registered by this package. They are off unless a rule set enables them.
+const (
+	RuleForbidAirDistance = "forbid_air_distance"
+	RuleTimeWindowOver48h = "time_window_over_48h"
+)
+
+// RuleFunc returns the findings of a rule. Their severity is replaced by the one of the rule set,
+// and an empty code is filled with the rule id.
+type RuleFunc func(input *structs.OptimizationPostInput) structs.ValidationErrors
+
+type Rule struct {
+	Id       string
+	Severity structs.Severity // the severity used when the rule set does not mention the rule
+	Check    RuleFunc
+}
+
+// RuleSet maps a rule id, or the code of a built-in lint, to a severity. Built-in errors can not be changed,
+// only the built-in warnings can be raised to errors or turned off.
+type RuleSet map[string]structs.Severity
+
+var registry = struct {
+	sync.RWMutex
+	rules []Rule
+	ids   map[string]bool
+}{ids: map[string]bool{}}
+
+func init() {
+	MustRegisterRule(Rule{Id: RuleForbidAirDistance, Severity: SeverityOff, Check: forbidAirDistance})
+	MustRegisterRule(Rule{Id: RuleTimeWindowOver48h, Severity: SeverityOff, Check: timeWindowOver48h})
+}
+
+// RegisterRule adds rule to the rules run by Validate. Rules run in registration order after the built-in checks.
+func RegisterRule(rule Rule) error {
+	if len(rule.Id) == 0 || rule.Check == nil {
+		return fmt.Errorf("a validation rule needs an id and a check")
+	}
+	if !validRuleSeverity(rule.Severity) {
+		return fmt.Errorf("invalid severity %q for validation rule %s", rule.Severity, rule.Id)
+	}
+	registry.Lock()
+	defer registry.Unlock()
+	if registry.ids[rule.Id] {
+		return fmt.Errorf("validation rule %s is already registered", rule.Id)
+	}
+	registry.ids[rule.Id] = true
+	registry.rules = append(registry.rules, rule)
+	return nil
+}
+
+// MustRegisterRule is RegisterRule for package initialization, it panics on error
+func MustRegisterRule(rule Rule) {
+	if err := RegisterRule(rule); err != nil {
+		panic(err)
+	}
+}
+
+// RuleSetForKey returns the rule set configured in utils.Conf for apiKey, or the default rule set.
+// It returns nil when nothing is configured.
+func RuleSetForKey(apiKey string) RuleSet {
+	if utils.Conf == nil || utils.Conf.Validation == nil {
+		return nil
+	}
+	conf := utils.Conf.Validation
+	name, ok := conf.RuleSetByKey[apiKey]
+	if !ok {
+		name = conf.DefaultRuleSet
+	}
+	return NamedRuleSet(name)
+}
+
+// NamedRuleSet returns the rule set configured in utils.Conf under name, or nil when there is none
+func NamedRuleSet(name string) RuleSet {
+	if utils.Conf == nil || utils.Conf.Validation == nil {
+		return nil
+	}
+	configured, ok := utils.Conf.Validation.RuleSets[name]
+	if !ok {
+		return nil
+	}
+	ruleSet := make(RuleSet, len(configured))
+	for id, severity := range configured {
+		ruleSet[id] = structs.Severity(severity)
+	}
+	return ruleSet
+}
+
+func validRuleSeverity(severity structs.Severity) bool {
+	return severity == structs.SeverityError || severity == structs.SeverityWarning || severity == SeverityOff
+}
+
+// runRules runs the registered rules with the severities of the rule set of c
+func runRules(c *collector, input *structs.OptimizationPostInput) bool {
+	registry.RLock()
+	rules := append([]Rule(nil), registry.rules...)
+	registry.RUnlock()
+
+	for _, rule := range rules {
+		severity := rule.Severity
+		if override, ok := c.opts.RuleSet[rule.Id]; ok && validRuleSeverity(override) {
+			severity = override
+		}
+		if severity == SeverityOff {
+			continue
+		}
+		for _, finding := range rule.Check(input) {
+			finding.Severity = severity
+			if len(finding.Code) == 0 {
+				finding.Code = rule.Id
+			}
+			if !c.add(finding) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func forbidAirDistance(input *structs.OptimizationPostInput) structs.ValidationErrors {
+	if input.Options.Objective.TravelCost != "air_distance" {
+		return nil
+	}
+	return structs.ValidationErrors{newError("options.objective.travel_cost", "", "\"air_distance\" is not available for this account. Please use \"distance\", \"duration\" or \"customized\"")}
+}
+
+func timeWindowOver48h(input *structs.OptimizationPostInput) structs.ValidationErrors {
+	const limit = 48 * 60 * 60
+	var findings structs.ValidationErrors
+	check := func(path string, timeWindows [][]uint64) {
+		for i, timeWindow := range timeWindows {
+			if len(timeWindow) == 2 && timeWindow[1] > timeWindow[0] && timeWindow[1]-timeWindow[0] > limit {
+				findings = append(findings, newError(fmt.Sprintf("%s[%d]", path, i), "", "time windows longer than 48 hours are not allowed for this account"))
+			}
+		}
+	}
+	for i, job := range input.Jobs {
+		check(fmt.Sprintf("jobs[%d].time_windows", i), job.TimeWindows)
+	}
+	for i, shipment := range input.Shipments {
+		if shipment.Pickup != nil {
+			check(fmt.Sprintf("shipments[%d].pickup.time_windows", i), shipment.Pickup.TimeWindows)
+		}
+		if shipment.Delivery != nil {
+			check(fmt.Sprintf("shipments[%d].delivery.time_windows", i), shipment.Delivery.TimeWindows)
+		}
+	}
+	for i, vehicle := range input.Vehicles {
+		timeWindow := vehicle.TimeWindow
+		if len(timeWindow) == 2 && timeWindow[1] > timeWindow[0] && timeWindow[1]-timeWindow[0] > limit {
+			findings = append(findings, newError(fmt.Sprintf("vehicles[%d].time_window", i), "", "time windows longer than 48 hours are not allowed for this account"))
+		}
+	}
+	return findings
+}
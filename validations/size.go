@@ -0,0 +1,118 @@
+package validations
+
+import (
+	"encoding/json"
+	"fmt"
+
+	structs "github.com/nextbillion-ai/nb-optimization-interface/structs"
+	"github.com/nextbillion-ai/nb-optimization-interface/utils"
+)
+
+type Executor string
+
+const (
+	ExecutorNormal  Executor = "normal"
+	ExecutorMassive Executor = "massive"
+	ExecutorMDM     Executor = "mdm"
+)
+
+type SizeMetrics struct {
+	Jobs           int // jobs plus shipment pickups and deliveries, the tasks the engine sees
+	Vehicles       int
+	UniqueVehicles int // vehicles that differ in anything but their id and description
+	Locations      int // locations given in the request
+	// distinct locations used by tasks, split by whether every task there has a time window (normal)
+	// or at least one can be served at any time (flexible)
+	NormalLocations int
+	FlexLocations   int
+}
+
+type SizeClass struct {
+	Metrics  SizeMetrics
+	Executor Executor
+	Reasons  []string // every threshold that is exceeded
+}
+
+// ClassifyProblemSize measures input and decides where it will be routed with the thresholds of conf.
+// A nil conf falls back to utils.Conf and then to the defaults. Exceeding the job or unique vehicle
+// threshold sends the request to the massive executor, exceeding a location threshold sends it to MDM.
+func ClassifyProblemSize(input *structs.OptimizationPostInput, conf *utils.MCConsumerConf) SizeClass {
+	if conf == nil && utils.Conf != nil {
+		conf = utils.Conf.MCConsumer
+	}
+	if conf == nil {
+		conf = utils.DefaultMCConsumerConf()
+	}
+
+	metrics := measure(input)
+	class := SizeClass{Metrics: metrics, Executor: ExecutorNormal}
+	if metrics.Jobs > conf.NumOfJobs {
+		class.Reasons = append(class.Reasons, fmt.Sprintf("%d jobs exceed the limit of %d", metrics.Jobs, conf.NumOfJobs))
+		class.Executor = ExecutorMassive
+	}
+	if metrics.UniqueVehicles > conf.NumOfUniqueVehicles {
+		class.Reasons = append(class.Reasons, fmt.Sprintf("%d unique vehicles exceed the limit of %d", metrics.UniqueVehicles, conf.NumOfUniqueVehicles))
+		class.Executor = ExecutorMassive
+	}
+	if metrics.NormalLocations > conf.NumOfNormalLocations {
+		class.Reasons = append(class.Reasons, fmt.Sprintf("%d normal locations exceed the limit of %d", metrics.NormalLocations, conf.NumOfNormalLocations))
+		class.Executor = ExecutorMDM
+	}
+	if metrics.FlexLocations > conf.NumOfFlexLocations {
+		class.Reasons = append(class.Reasons, fmt.Sprintf("%d flexible locations exceed the limit of %d", metrics.FlexLocations, conf.NumOfFlexLocations))
+		class.Executor = ExecutorMDM
+	}
+	return class
+}
+
+func measure(input *structs.OptimizationPostInput) SizeMetrics {
+	metrics := SizeMetrics{
+		Jobs:     len(input.Jobs),
+		Vehicles: len(input.Vehicles),
+	}
+	if len(input.Locations.Location) == 0 && input.Locations.AnyTypeLocation != nil {
+		locations := input.Locations
+		if locations.ConvertLocation() == nil {
+			metrics.Locations = locationCount(locations)
+		}
+	} else {
+		metrics.Locations = locationCount(input.Locations)
+	}
+
+	// location index -> whether a task without time window uses it
+	flexible := map[uint64]bool{}
+	use := func(locationIndex uint64, timeWindows int) {
+		flexible[locationIndex] = flexible[locationIndex] || timeWindows == 0
+	}
+	for _, job := range input.Jobs {
+		use(job.LocationIndex, len(job.TimeWindows)+len(job.TimeWindow))
+	}
+	for _, shipment := range input.Shipments {
+		for _, step := range []*structs.ShipmentStep{shipment.Pickup, shipment.Delivery} {
+			if step != nil {
+				metrics.Jobs++
+				use(step.LocationIndex, len(step.TimeWindows)+len(step.TimeWindow))
+			}
+		}
+	}
+	for _, isFlexible := range flexible {
+		if isFlexible {
+			metrics.FlexLocations++
+		} else {
+			metrics.NormalLocations++
+		}
+	}
+
+	unique := map[string]bool{}
+	for _, vehicle := range input.Vehicles {
+		vehicle.Id = 0
+		vehicle.Description = nil
+		key, err := json.Marshal(vehicle)
+		if err != nil {
+			key = []byte(fmt.Sprint(vehicle))
+		}
+		unique[string(key)] = true
+	}
+	metrics.UniqueVehicles = len(unique)
+	return metrics
+}
@@ -0,0 +1,102 @@
+package validations
+
+import (
+	"fmt"
+
+	structs "github.com/nextbillion-ai/nb-optimization-interface/structs"
+)
+
+// CheckSkills finds the jobs and shipments whose skills are not all held by a single vehicle.
+// The engine can never assign those, so they are returned as warnings together with the
+// unassigned entries that can be reported up front with AppendUnassigned.
+func CheckSkills(input *structs.OptimizationPostInput) (structs.ValidationErrors, []structs.Unassigned) {
+	var warnings structs.ValidationErrors
+	var unassigned []structs.Unassigned
+
+	fleet := make([]map[uint64]bool, 0, len(input.Vehicles))
+	known := map[uint64]bool{}
+	for _, vehicle := range input.Vehicles {
+		skills := make(map[uint64]bool, len(vehicle.Skills))
+		for _, skill := range vehicle.Skills {
+			skills[skill] = true
+			known[skill] = true
+		}
+		fleet = append(fleet, skills)
+	}
+
+	for i, job := range input.Jobs {
+		if reason := skillShortage(job.Skills, fleet, known); len(reason) > 0 {
+			warnings = append(warnings, newWarning(fmt.Sprintf("jobs[%d].skills", i), CodeMissingSkill, "job %d can not be assigned: %s", job.Id, reason))
+			unassigned = append(unassigned, unservable(input, job.Id, "job", job.LocationIndex, reason))
+		}
+	}
+	for i, shipment := range input.Shipments {
+		reason := skillShortage(shipment.Skills, fleet, known)
+		if len(reason) == 0 || shipment.Pickup == nil || shipment.Delivery == nil {
+			continue
+		}
+		warnings = append(warnings, newWarning(fmt.Sprintf("shipments[%d].skills", i), CodeMissingSkill, "shipment with pickup %d and delivery %d can not be assigned: %s", shipment.Pickup.Id, shipment.Delivery.Id, reason))
+		unassigned = append(unassigned,
+			unservable(input, shipment.Pickup.Id, "pickup", shipment.Pickup.LocationIndex, reason),
+			unservable(input, shipment.Delivery.Id, "delivery", shipment.Delivery.LocationIndex, reason))
+	}
+	return warnings, unassigned
+}
+
+// AppendUnassigned adds the tasks found by CheckSkills to result, skipping the ones it already reports.
+// An entry of result without type, as the engine often returns them, matches a task of any type with its id.
+func AppendUnassigned(result *structs.HeraldResult, unassigned []structs.Unassigned) {
+	type taskKey struct {
+		taskType string
+		id       uint64
+	}
+	reported := map[taskKey]bool{}
+	for _, task := range result.Unassigned {
+		reported[taskKey{task.Type, task.Id}] = true
+	}
+	for _, task := range unassigned {
+		if reported[taskKey{task.Type, task.Id}] || reported[taskKey{"", task.Id}] {
+			continue
+		}
+		result.Unassigned = append(result.Unassigned, task)
+		reported[taskKey{task.Type, task.Id}] = true
+	}
+	if result.Summary != nil {
+		result.Summary.Unassigned = uint64(len(result.Unassigned))
+	}
+}
+
+func validateSkills(c *collector, input *structs.OptimizationPostInput) bool {
+	warnings, _ := CheckSkills(input)
+	return c.add(warnings...)
+}
+
+// skillShortage explains why no vehicle can serve a task with the given skills, or returns "" when one can
+func skillShortage(skills []uint64, fleet []map[uint64]bool, known map[uint64]bool) string {
+	if len(skills) == 0 {
+		return ""
+	}
+	for _, skill := range skills {
+		if !known[skill] {
+			return fmt.Sprintf("no vehicle with skill %d", skill)
+		}
+	}
+	for _, vehicleSkills := range fleet {
+		hasAll := true
+		for _, skill := range skills {
+			hasAll = hasAll && vehicleSkills[skill]
+		}
+		if hasAll {
+			return ""
+		}
+	}
+	return fmt.Sprintf("no single vehicle with all of skills %v", skills)
+}
+
+func unservable(input *structs.OptimizationPostInput, id uint64, taskType string, locationIndex uint64, reason string) structs.Unassigned {
+	task := structs.Unassigned{Id: id, Type: taskType, Reason: reason}
+	if location, err := input.Locations.LatLngAt(locationIndex); err == nil {
+		task.Location = location
+	}
+	return task
+}
@@ -0,0 +1,21 @@
+package validations
+
+import (
+	"testing"
+
+	structs "github.com/nextbillion-ai/nb-optimization-interface/structs"
+)
+
+func TestAppendUnassignedSkipsUntypedEntries(t *testing.T) {
+	result := &structs.HeraldResult{
+		Unassigned: []structs.Unassigned{{Id: 1}},
+		Summary:    &structs.Summary{Unassigned: 1},
+	}
+	AppendUnassigned(result, []structs.Unassigned{{Id: 1, Type: "job"}, {Id: 2, Type: "job"}})
+	if len(result.Unassigned) != 2 || result.Unassigned[1].Id != 2 {
+		t.Errorf("expected only job 2 to be appended, got %v", result.Unassigned)
+	}
+	if result.Summary.Unassigned != 2 {
+		t.Errorf("expected the summary to count 2 unassigned tasks, got %d", result.Summary.Unassigned)
+	}
+}
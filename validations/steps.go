@@ -0,0 +1,94 @@
+package validations
+
+import (
+	"fmt"
+
+	structs "github.com/nextbillion-ai/nb-optimization-interface/structs"
+)
+
+// ValidateVehicleSteps checks the steps pinned on the vehicles of msg: the step type, that the id refers to
+// a job, shipment step or break of that vehicle, the service_after <= service_at <= service_before order
+// and that no task is pinned to two vehicles. A non-nil error is always a structs.ValidationErrors.
+func ValidateVehicleSteps(msg *structs.VehicleRoutingMsg, opts ValidateOptions) error {
+	c := newCollector(opts)
+	validateVehicleSteps(c, msg)
+	return c.err()
+}
+
+func validateVehicleSteps(c *collector, msg *structs.VehicleRoutingMsg) bool {
+	tasks := map[string]map[uint64]bool{
+		structs.StepTypeJob:      {},
+		structs.StepTypePickup:   {},
+		structs.StepTypeDelivery: {},
+	}
+	for _, job := range msg.Jobs {
+		tasks[structs.StepTypeJob][job.Id] = true
+	}
+	for _, shipment := range msg.Shipments {
+		if shipment.Pickup != nil {
+			tasks[structs.StepTypePickup][shipment.Pickup.Id] = true
+		}
+		if shipment.Delivery != nil {
+			tasks[structs.StepTypeDelivery][shipment.Delivery.Id] = true
+		}
+	}
+
+	// pinned remembers which vehicle step a task was first pinned to
+	pinned := map[string]map[uint64]string{
+		structs.StepTypeJob:      {},
+		structs.StepTypePickup:   {},
+		structs.StepTypeDelivery: {},
+	}
+	for i, vehicle := range msg.Vehicles {
+		breaks := make(map[uint64]bool, len(vehicle.Breaks))
+		for _, b := range vehicle.Breaks {
+			breaks[b.Id] = true
+		}
+		for j, step := range vehicle.Steps {
+			path := fmt.Sprintf("vehicles[%d].steps[%d]", i, j)
+			switch step.Type {
+			case structs.StepTypeStart, structs.StepTypeEnd:
+			case structs.StepTypeBreak:
+				if !breaks[step.Id] && !c.add(newError(path+".id", CodeUnknownStepId, "break %d is not a break of vehicle %d. Please ensure that the pinned break is declared in the breaks of this vehicle", step.Id, vehicle.Id)) {
+					return false
+				}
+			case structs.StepTypeJob, structs.StepTypePickup, structs.StepTypeDelivery:
+				if !tasks[step.Type][step.Id] {
+					if !c.add(newError(path+".id", CodeUnknownStepId, "%s %d pinned on vehicle %d does not exist. Please ensure that the id refers to a declared %s", step.Type, step.Id, vehicle.Id, step.Type)) {
+						return false
+					}
+					break
+				}
+				if first, ok := pinned[step.Type][step.Id]; ok {
+					if !c.add(newError(path+".id", CodeTaskPinnedTwice, "%s %d is pinned more than once, already at %s. Please ensure that every task is pinned to one vehicle only", step.Type, step.Id, first)) {
+						return false
+					}
+					break
+				}
+				pinned[step.Type][step.Id] = path
+			default:
+				if !c.add(newError(path+".type", CodeInvalidStepType, "the step type %q is invalid. Please ensure that the type belongs to the following options: \"start\", \"job\", \"pickup\", \"delivery\", \"break\" or \"end\"", step.Type)) {
+					return false
+				}
+			}
+			if !validateServiceTimes(c, path, step) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// validateServiceTimes checks service_after <= service_at <= service_before among the values that are set
+func validateServiceTimes(c *collector, path string, step structs.VehicleStep) bool {
+	if step.ServiceAt != 0 && step.ServiceAfter != 0 && step.ServiceAt < step.ServiceAfter {
+		return c.add(newError(path+".service_at", CodeInvalidServiceTime, "service_at %d is earlier than service_after %d", step.ServiceAt, step.ServiceAfter))
+	}
+	if step.ServiceAt != 0 && step.ServiceBefore != 0 && step.ServiceAt > step.ServiceBefore {
+		return c.add(newError(path+".service_at", CodeInvalidServiceTime, "service_at %d is later than service_before %d", step.ServiceAt, step.ServiceBefore))
+	}
+	if step.ServiceAfter != 0 && step.ServiceBefore != 0 && step.ServiceAfter > step.ServiceBefore {
+		return c.add(newError(path+".service_after", CodeInvalidServiceTime, "service_after %d is later than service_before %d", step.ServiceAfter, step.ServiceBefore))
+	}
+	return c.more()
+}
@@ -0,0 +1,136 @@
+package validations
+
+import (
+	"fmt"
+
+	structs "github.com/nextbillion-ai/nb-optimization-interface/structs"
+)
+
+// checkTimeWindows validates a single list of time windows and warns when one of them spans more than 24 hours
+func checkTimeWindows(c *collector, path string, timeWindows [][]uint64) bool {
+	exceed24h, err := validateTimeWindows(path, timeWindows)
+	if exceed24h && !c.add(longTimeWindowWarning(path)) {
+		return false
+	}
+	return c.addError(err, path, CodeInvalidTimeWindow)
+}
+
+func longTimeWindowWarning(path string) structs.ValidationError {
+	return newWarning(path, CodeLongTimeWindow, "a time window longer than 24 hours is given. Please ensure that the timestamps are in seconds and that such a long period is intended")
+}
+
+// validateTimeWindowFeasibility compares the time windows of different entities: tasks against
+// vehicle shifts, breaks against the shift of their vehicle and shipment deliveries against their pickups.
+// Lists that are invalid on their own are skipped, they have already been reported.
+func validateTimeWindowFeasibility(c *collector, input *structs.OptimizationPostInput) bool {
+	var shifts [][]uint64
+	for _, vehicle := range input.Vehicles {
+		if len(vehicle.TimeWindow) == 0 {
+			// a vehicle without shift is always available, every task overlaps it
+			shifts = nil
+			break
+		}
+		if wellFormed([][]uint64{vehicle.TimeWindow}) {
+			shifts = append(shifts, vehicle.TimeWindow)
+		}
+	}
+
+	if len(shifts) > 0 {
+		for i, job := range input.Jobs {
+			if !checkWithinShifts(c, fmt.Sprintf("jobs[%d].time_windows", i), fmt.Sprintf("job %d", job.Id), job.TimeWindows, shifts) {
+				return false
+			}
+		}
+		for i, shipment := range input.Shipments {
+			if shipment.Pickup == nil || shipment.Delivery == nil {
+				continue
+			}
+			if !checkWithinShifts(c, fmt.Sprintf("shipments[%d].pickup.time_windows", i), fmt.Sprintf("shipment pickup %d", shipment.Pickup.Id), shipment.Pickup.TimeWindows, shifts) ||
+				!checkWithinShifts(c, fmt.Sprintf("shipments[%d].delivery.time_windows", i), fmt.Sprintf("shipment delivery %d", shipment.Delivery.Id), shipment.Delivery.TimeWindows, shifts) {
+				return false
+			}
+		}
+	}
+
+	for i, vehicle := range input.Vehicles {
+		if len(vehicle.TimeWindow) == 0 || !wellFormed([][]uint64{vehicle.TimeWindow}) {
+			continue
+		}
+		for j, b := range vehicle.Breaks {
+			if !checkBreakWithinShift(c, fmt.Sprintf("vehicles[%d].breaks[%d].time_windows", i, j), vehicle, b) {
+				return false
+			}
+		}
+	}
+
+	for i, shipment := range input.Shipments {
+		if shipment.Pickup == nil || shipment.Delivery == nil {
+			continue
+		}
+		pickup, delivery := shipment.Pickup.TimeWindows, shipment.Delivery.TimeWindows
+		if len(pickup) == 0 || len(delivery) == 0 || !wellFormed(pickup) || !wellFormed(delivery) {
+			continue
+		}
+		if delivery[len(delivery)-1][1] < pickup[0][0] {
+			if !c.add(newError(fmt.Sprintf("shipments[%d].delivery.time_windows", i), CodeDeliveryBeforePickup, "the time windows of shipment delivery %d close at %d, before the time windows of pickup %d open at %d. Please ensure that the delivery can happen after the pickup", shipment.Delivery.Id, delivery[len(delivery)-1][1], shipment.Pickup.Id, pickup[0][0])) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// checkWithinShifts warns when none of the task's time windows overlaps any vehicle shift
+func checkWithinShifts(c *collector, path string, task string, timeWindows [][]uint64, shifts [][]uint64) bool {
+	if len(timeWindows) == 0 || !wellFormed(timeWindows) {
+		return c.more()
+	}
+	for _, timeWindow := range timeWindows {
+		for _, shift := range shifts {
+			if overlaps(timeWindow, shift) {
+				return c.more()
+			}
+		}
+	}
+	return c.add(newWarning(path, CodeOutsideVehicleShifts, "none of the time windows of %s overlaps the time window of any vehicle, so it will never be assigned", task))
+}
+
+// checkBreakWithinShift reports break time windows outside the vehicle shift. It is an error when
+// the break can not be taken at all, a warning when only some of its windows are unusable.
+func checkBreakWithinShift(c *collector, path string, vehicle structs.Vehicle, b structs.Break) bool {
+	if len(b.TimeWindows) == 0 || !wellFormed(b.TimeWindows) {
+		return c.more()
+	}
+	var outside []int
+	for k, timeWindow := range b.TimeWindows {
+		if timeWindow[0] < vehicle.TimeWindow[0] || timeWindow[1] > vehicle.TimeWindow[1] {
+			outside = append(outside, k)
+		}
+	}
+	if len(outside) == 0 {
+		return c.more()
+	}
+	usable := false
+	for _, timeWindow := range b.TimeWindows {
+		usable = usable || overlaps(timeWindow, vehicle.TimeWindow)
+	}
+	if !usable {
+		return c.add(newError(path, CodeBreakOutsideShift, "the time windows of break %d are all outside the time window of vehicle %d. Please ensure that the break can be taken during the vehicle shift", b.Id, vehicle.Id))
+	}
+	for _, k := range outside {
+		if !c.add(newWarning(fmt.Sprintf("%s[%d]", path, k), CodeBreakOutsideShift, "the time window of break %d is not fully inside the time window of vehicle %d", b.Id, vehicle.Id)) {
+			return false
+		}
+	}
+	return c.more()
+}
+
+// wellFormed reports whether timeWindows passes validateTimeWindows
+func wellFormed(timeWindows [][]uint64) bool {
+	_, err := validateTimeWindows("", timeWindows)
+	return err == nil
+}
+
+func overlaps(a []uint64, b []uint64) bool {
+	return a[0] <= b[1] && b[0] <= a[1]
+}
@@ -0,0 +1,229 @@
+package validations
+
+import (
+	"errors"
+	"fmt"
+
+	structs "github.com/nextbillion-ai/nb-optimization-interface/structs"
+)
+
+type ValidateOptions struct {
+	CollectAll bool    // walk the whole input and report every violation instead of stopping at the first one
+	MaxErrors  int     // stop collecting once this many errors are found. 0 means no cap. Only used with CollectAll
+	RuleSet    RuleSet // severities of the registered rules and built-in lints, e.g. from RuleSetForKey
+}
+
+// Validate runs every check of the contract over input. It works on a copy, so input is
+// left untouched, and returns the copy normalized by Normalize together with the lint warnings.
+// The SDK and the server should both call it instead of chaining the validators themselves.
+// Validate stops at the first error, use ValidateWithOptions to collect all of them.
+// A non-nil error is always a structs.ValidationErrors.
+func Validate(input *structs.OptimizationPostInput) (structs.OptimizationPostInput, structs.ValidationErrors, error) {
+	return ValidateWithOptions(input, ValidateOptions{})
+}
+
+func ValidateWithOptions(input *structs.OptimizationPostInput, opts ValidateOptions) (structs.OptimizationPostInput, structs.ValidationErrors, error) {
+	normalized := copyInput(input)
+	c := newCollector(opts)
+	runChecks(c, &normalized)
+	return normalized, c.warnings, c.err()
+}
+
+func runChecks(c *collector, normalized *structs.OptimizationPostInput) {
+	if !normalize(c, normalized) {
+		return
+	}
+	// every other check depends on the location list, so a bad one ends validation in any mode
+	if err := normalized.Locations.ConvertLocation(); err != nil {
+		c.addError(err, "locations.location", CodeInvalidLocation)
+		return
+	}
+	if _, err := normalized.Locations.ParseCoordinates(); !c.addError(err, "locations.location", CodeInvalidCoordinate) {
+		return
+	}
+	if !c.addError(validateApproaches(normalized.Locations), "locations.approaches", CodeInvalidApproach) {
+		return
+	}
+
+	options, optionWarnings, err := validateOptions(normalized)
+	c.add(optionWarnings...)
+	if !c.addError(err, "options", CodeInvalidTravelCost) {
+		return
+	}
+	normalized.Options = options
+
+	if !validateJobs(c, normalized.Jobs) || !validateShipments(c, normalized.Shipments) || !validateVehicles(c, normalized.Vehicles) {
+		return
+	}
+	for _, check := range []func(*collector, *structs.OptimizationPostInput) bool{
+		validateDepots,
+		validateReferences,
+		resolveDepots,
+		validateCapacities,
+		validateSkills,
+		validateTimeWindowFeasibility,
+		validateRanges,
+		validateCostMatrixQuality,
+		runRules,
+	} {
+		if !check(c, normalized) {
+			return
+		}
+	}
+}
+
+type collector struct {
+	opts     ValidateOptions
+	errs     structs.ValidationErrors
+	warnings structs.ValidationErrors
+}
+
+func newCollector(opts ValidateOptions) *collector {
+	return &collector{opts: opts}
+}
+
+// add records items by severity and reports whether validation should go on.
+// Warnings are raised or dropped as the rule set says.
+func (c *collector) add(items ...structs.ValidationError) bool {
+	for _, item := range items {
+		if !c.more() {
+			return false
+		}
+		if override, ok := c.opts.RuleSet[item.Code]; ok && item.Severity == structs.SeverityWarning && validRuleSeverity(override) {
+			if override == SeverityOff {
+				continue
+			}
+			item.Severity = override
+		}
+		if item.Severity == structs.SeverityWarning {
+			c.warnings = append(c.warnings, item)
+		} else {
+			c.errs = append(c.errs, item)
+		}
+	}
+	return c.more()
+}
+
+// addError records err, if any. Errors without structure are reported under path and code.
+func (c *collector) addError(err error, path string, code string) bool {
+	if err == nil {
+		return c.more()
+	}
+	return c.add(toValidationErrors(err, path, code)...)
+}
+
+func (c *collector) more() bool {
+	if len(c.errs) == 0 {
+		return true
+	}
+	if !c.opts.CollectAll {
+		return false
+	}
+	return c.opts.MaxErrors <= 0 || len(c.errs) < c.opts.MaxErrors
+}
+
+func (c *collector) err() error {
+	if len(c.errs) == 0 {
+		return nil
+	}
+	return c.errs
+}
+
+// copyInput clones the top level slices of input so that normalizing the copy never writes
+// through to the caller's jobs, shipments, vehicles or depots.
+func copyInput(input *structs.OptimizationPostInput) structs.OptimizationPostInput {
+	normalized := *input
+	normalized.Jobs = append([]structs.Job(nil), input.Jobs...)
+	normalized.Shipments = append([]structs.Shipment(nil), input.Shipments...)
+	for i, shipment := range normalized.Shipments {
+		if shipment.Pickup != nil {
+			pickup := *shipment.Pickup
+			normalized.Shipments[i].Pickup = &pickup
+		}
+		if shipment.Delivery != nil {
+			delivery := *shipment.Delivery
+			normalized.Shipments[i].Delivery = &delivery
+		}
+	}
+	normalized.Vehicles = append([]structs.Vehicle(nil), input.Vehicles...)
+	normalized.Depots = append([]structs.Depot(nil), input.Depots...)
+	normalized.Depot = append([]structs.Depot(nil), input.Depot...)
+	return normalized
+}
+
+func validateJobs(c *collector, jobs []structs.Job) bool {
+	for i, job := range jobs {
+		if !checkTimeWindows(c, fmt.Sprintf("jobs[%d].time_windows", i), job.TimeWindows) {
+			return false
+		}
+	}
+	return true
+}
+
+func validateShipments(c *collector, shipments []structs.Shipment) bool {
+	for i, shipment := range shipments {
+		if shipment.Pickup == nil || shipment.Delivery == nil {
+			if !c.add(newError(fmt.Sprintf("shipments[%d]", i), CodeMissingShipmentStep, "shipment at index %d is missing its pickup or delivery. Please ensure that every shipment has both a \"pickup\" and a \"delivery\"", i)) {
+				return false
+			}
+			continue
+		}
+		if !checkTimeWindows(c, fmt.Sprintf("shipments[%d].pickup.time_windows", i), shipment.Pickup.TimeWindows) ||
+			!checkTimeWindows(c, fmt.Sprintf("shipments[%d].delivery.time_windows", i), shipment.Delivery.TimeWindows) {
+			return false
+		}
+	}
+	return true
+}
+
+func validateVehicles(c *collector, vehicles []structs.Vehicle) bool {
+	if len(vehicles) == 0 {
+		return c.add(newError("vehicles", CodeMissingVehicle, "no vehicle specified. Please provide at least one vehicle"))
+	}
+	for i, vehicle := range vehicles {
+		if len(vehicle.TimeWindow) > 0 {
+			// the vehicle carries a single window, so report it without a window index
+			exceed24h, err := validateTimeWindows(fmt.Sprintf("vehicles[%d].time_window", i), [][]uint64{vehicle.TimeWindow})
+			var validationErrs structs.ValidationErrors
+			if errors.As(err, &validationErrs) {
+				for j := range validationErrs {
+					validationErrs[j].Path = fmt.Sprintf("vehicles[%d].time_window", i)
+				}
+				err = validationErrs
+			}
+			if exceed24h && !c.add(longTimeWindowWarning(fmt.Sprintf("vehicles[%d].time_window", i))) {
+				return false
+			}
+			if !c.addError(err, fmt.Sprintf("vehicles[%d].time_window", i), CodeInvalidTimeWindow) {
+				return false
+			}
+		}
+		if !validateBreaks(c, fmt.Sprintf("vehicles[%d].breaks", i), vehicle.Breaks) {
+			return false
+		}
+	}
+	return true
+}
+
+func validateBreaks(c *collector, path string, breaks []structs.Break) bool {
+	for i, b := range breaks {
+		breakPath := fmt.Sprintf("%s[%d].time_windows", path, i)
+		if len(b.TimeWindows) == 0 {
+			if !c.add(newError(breakPath, CodeMissingBreakTimeWindow, "break %d has no time windows. Please provide at least one time window for every break", b.Id)) {
+				return false
+			}
+			continue
+		}
+		if !checkTimeWindows(c, breakPath, b.TimeWindows) {
+			return false
+		}
+	}
+	return true
+}
+
+func validateDepots(c *collector, input *structs.OptimizationPostInput) bool {
+	if input.Options.Objective.MinimiseNumDepots && len(input.Depots) == 0 && len(input.Depot) == 0 {
+		return c.add(newWarning("options.objective.minimise_num_depots", CodeIgnoredOption, "minimise_num_depots is ignored as no depots are specified"))
+	}
+	return c.more()
+}
@@ -46,94 +46,114 @@ import (
 // }
 
 
-func validateTimeWindows(timeWindows [][]uint64) (bool, error) {
+// validateTimeWindows reports every malformed, out of range or overlapping window of the list
+func validateTimeWindows(path string, timeWindows [][]uint64) (bool, error) {
+	var errs structs.ValidationErrors
 	var exceed24h = false
 	var dayTime uint64
 	dayTime = 24 * 60 * 60 // time in seconds
 
 	for i, timeWindow := range timeWindows {
+		windowPath := fmt.Sprintf("%s[%d]", path, i)
 		if len(timeWindow) != 2 {
-			return exceed24h, fmt.Errorf("invalid number of timestamp(s) for the time window. Each time window should contain 2 timestamps in the format [start_timestamp, end_timestamp]. Please ensure that all time windows are specified correctly")
+			errs = append(errs, newError(windowPath, CodeInvalidTimeWindow, "invalid number of timestamp(s) for the time window. Each time window should contain 2 timestamps in the format [start_timestamp, end_timestamp]. Please ensure that all time windows are specified correctly"))
+			continue
 		}
 		if timeWindow[0] >= timeWindow[1] {
-			return exceed24h, fmt.Errorf("invalid time window. Each time window should be in the format [start_timestamp, end_timestamp] where start_timestamp should be less/earlier than end_timestamp. Please ensure that all time windows have valid and chronological timestamps")
+			errs = append(errs, newError(windowPath, CodeInvalidTimeWindow, "invalid time window. Each time window should be in the format [start_timestamp, end_timestamp] where start_timestamp should be less/earlier than end_timestamp. Please ensure that all time windows have valid and chronological timestamps"))
+			continue
 		}
-		if timeWindow[0] > uint64(4294967295) {
-			return exceed24h, fmt.Errorf("invalid timestamp value %d. Please provide a timestamp value less than 4294967295", timeWindow[0])
+		if timeWindow[0] > maxEngineValue {
+			errs = append(errs, newError(windowPath, CodeTimestampOutOfRange, "invalid timestamp value %d. Please provide a timestamp value less than %d", timeWindow[0], maxEngineValue))
 		}
-		if timeWindow[1] > uint64(4294967295) {
-			return exceed24h, fmt.Errorf("invalid timestamp value %d. Please provide a timestamp value less than 4294967295", timeWindow[1])
+		if timeWindow[1] > maxEngineValue {
+			errs = append(errs, newError(windowPath, CodeTimestampOutOfRange, "invalid timestamp value %d. Please provide a timestamp value less than %d", timeWindow[1], maxEngineValue))
 		}
 		if timeWindow[1]-timeWindow[0] > dayTime {
 			exceed24h = true
 		}
-		if i != 0 && timeWindow[0] <= timeWindows[i-1][1] {
-			return exceed24h, fmt.Errorf("overlapping time window or unsorted time windows. Please ensure that the time windows are ordered from earliest to latest and they do not overlap")
+		if i != 0 && len(timeWindows[i-1]) == 2 && timeWindow[0] <= timeWindows[i-1][1] {
+			errs = append(errs, newError(windowPath, CodeOverlappingTimeWindows, "overlapping time window or unsorted time windows. Please ensure that the time windows are ordered from earliest to latest and they do not overlap"))
 		}
 
 	}
 
+	if len(errs) > 0 {
+		return exceed24h, errs
+	}
 	return exceed24h, nil
 }
 
 
-func validateOptions(input *structs.OptimizationPostInput) (structs.OptimizationOptions, []string, error) {
-	var warnings []string
+// validateOptions reports every invalid option, together with the warnings about ignored ones
+func validateOptions(input *structs.OptimizationPostInput) (structs.OptimizationOptions, []structs.ValidationError, error) {
+	var warnings []structs.ValidationError
+	var errs structs.ValidationErrors
 
 	options := input.Options
 	if len(options.Objective.TravelCost) != 0 {
 		if options.Objective.TravelCost != "distance" && options.Objective.TravelCost != "duration" &&
 			options.Objective.TravelCost != "customized" && options.Objective.TravelCost != "air_distance" {
-			return options, warnings, fmt.Errorf("invalid value for \"travel_cost\" specified. Please ensure that the \"travel_cost\" belongs to the following options: \"distance\", \"duration\", \"air_distance\", or \"customized\"")
+			errs = append(errs, newError("options.objective.travel_cost", CodeInvalidTravelCost, "invalid value for \"travel_cost\" specified. Please ensure that the \"travel_cost\" belongs to the following options: \"distance\", \"duration\", \"air_distance\", or \"customized\""))
 		}
 		if options.Objective.TravelCost == "customized" {
 			length := len(strings.Split(input.Locations.Location, "|"))
 			err := validateCostMatrix(length, input.CostMatrix)
 			if err != nil {
-				return options, warnings, err
+				errs = append(errs, toValidationErrors(err, "cost_matrix", CodeInvalidCostMatrix)...)
 			}
 		}
 	} else {
 		options.Objective.TravelCost = "duration"
 	}
 
+	// mode is reconciled with the legacy top level field by Normalize
 	routingOptions := options.Routing
-	if routingOptions.Mode != nil {
-		input.Mode = routingOptions.Mode
-	} else if input.Mode != nil {
-		routingOptions.Mode = input.Mode
+
+	// an empty mode is unset, ModeOrDefault reads it as car
+	if routingOptions.Mode != nil && len(*routingOptions.Mode) > 0 && !routingOptions.Mode.Valid() {
+		errs = append(errs, newError("options.routing.mode", CodeInvalidMode, "invalid value %q for \"mode\" specified. Please ensure that the \"mode\" belongs to the following options: \"car\", \"truck\", \"4w\" or \"6w\"", *routingOptions.Mode))
 	}
 
-	if routingOptions.TruckSize != nil && len(*routingOptions.TruckSize) > 0 {
-		truckSizes := strings.Split(*routingOptions.TruckSize, ",")
-		if len(truckSizes) != 3 {
-			return options, warnings, fmt.Errorf("the input for 'truck_size' is not in the correct format. Please ensure that 'truck_size' dimensions are specified as integer values")
-		}
+	if _, err := routingOptions.TruckProfile(); err != nil {
+		errs = append(errs, toValidationErrors(err, "options.routing", CodeInvalidTruckSize)...)
 	}
 
 	if (routingOptions.TruckSize != nil && len(*routingOptions.TruckSize) > 0) &&
-		(routingOptions.Mode != nil && (*routingOptions.Mode == "4w" || *routingOptions.Mode == "car")) {
-		warnings = append(warnings, "truck_size is ignored as mode=car")
+		(routingOptions.Mode != nil && routingOptions.Mode.Canonical() == structs.ModeCar) {
+		warnings = append(warnings, newWarning("options.routing.truck_size", CodeIgnoredOption, "truck_size is ignored as mode=car"))
 	}
 
 	if (routingOptions.TruckWeight != nil) &&
-		(routingOptions.Mode != nil && (*routingOptions.Mode == "4w" || *routingOptions.Mode == "car")) {
-		warnings = append(warnings, "truck_weight is ignored as mode=car")
+		(routingOptions.Mode != nil && routingOptions.Mode.Canonical() == structs.ModeCar) {
+		warnings = append(warnings, newWarning("options.routing.truck_weight", CodeIgnoredOption, "truck_weight is ignored as mode=car"))
+	}
+
+	if (routingOptions.TruckAxleCount != nil) &&
+		(routingOptions.Mode != nil && routingOptions.Mode.Canonical() == structs.ModeCar) {
+		warnings = append(warnings, newWarning("options.routing.truck_axle_count", CodeIgnoredOption, "truck_axle_count is ignored as mode=car"))
 	}
 
+	if len(errs) > 0 {
+		return options, warnings, errs
+	}
 	return options, warnings, nil
 }
 
 
 func validateCostMatrix(length int, matrix [][]uint64) error {
 	if len(matrix) != length {
-		return fmt.Errorf("invalid length of cost matrix. Its size should be %d x %d", length, length)
+		return newError("cost_matrix", CodeInvalidCostMatrix, "invalid length of cost matrix. Its size should be %d x %d", length, length)
 	}
-	for _, row := range matrix {
+	var errs structs.ValidationErrors
+	for i, row := range matrix {
 		if len(row) != length {
-			return fmt.Errorf("invalid length of cost matrix. Its size should be %d x %d", length, length)
+			errs = append(errs, newError(fmt.Sprintf("cost_matrix[%d]", i), CodeInvalidCostMatrix, "invalid length of cost matrix. Its size should be %d x %d", length, length))
 		}
 	}
+	if len(errs) > 0 {
+		return errs
+	}
 	return nil
 }
 
@@ -144,13 +164,17 @@ func validateApproaches(locations structs.Locations) error {
 	if len(locations.Approaches) > 0 {
 		approaches := locations.Approaches
 		if len(approaches) != length {
-			return fmt.Errorf("the number of approaches specified are not equal to the number of location coordinates provided in \"locations\" part. Please provide as many approaches as locations in the location array")
+			return newError("locations.approaches", CodeApproachCountMismatch, "the number of approaches specified are not equal to the number of location coordinates provided in \"locations\" part. Please provide as many approaches as locations in the location array")
 		}
-		for _, approach := range approaches {
+		var errs structs.ValidationErrors
+		for i, approach := range approaches {
 			if approach != "unrestricted" && approach != "curb" && approach != "" {
-				return fmt.Errorf("the approach %s is invalid. Please ensure that the approach belongs to the following options: \"curb\", \"unrestricted\", or \"\" (empty string)", approach)
+				errs = append(errs, newError(fmt.Sprintf("locations.approaches[%d]", i), CodeInvalidApproach, "the approach %s is invalid. Please ensure that the approach belongs to the following options: \"curb\", \"unrestricted\", or \"\" (empty string)", approach))
 			}
 		}
+		if len(errs) > 0 {
+			return errs
+		}
 	}
 	return nil
 }
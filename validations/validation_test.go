@@ -0,0 +1,123 @@
+package validations
+
+import (
+	"slices"
+	"testing"
+
+	structs "github.com/nextbillion-ai/nb-optimization-interface/structs"
+)
+
+func validInput() *structs.OptimizationPostInput {
+	return &structs.OptimizationPostInput{
+		Locations: structs.Locations{AnyTypeLocation: "1.29,103.80|1.30,103.81|1.31,103.82"},
+		Jobs:      []structs.Job{{Id: 1, LocationIndex: 1}, {Id: 2, LocationIndex: 2}},
+		Vehicles:  []structs.Vehicle{{Id: 1}},
+	}
+}
+
+func errorPaths(t *testing.T, err error) []string {
+	t.Helper()
+	errs, ok := err.(structs.ValidationErrors)
+	if !ok {
+		t.Fatalf("expected structs.ValidationErrors, got %T: %v", err, err)
+	}
+	paths := make([]string, 0, len(errs))
+	for _, item := range errs {
+		paths = append(paths, item.Path)
+	}
+	return paths
+}
+
+func TestValidateCollectAllReportsEveryFinding(t *testing.T) {
+	mode := structs.RoutingMode("bike")
+	truckSize := "1,2"
+	cases := []struct {
+		name   string
+		modify func(input *structs.OptimizationPostInput)
+		paths  []string
+	}{
+		{
+			name: "approaches",
+			modify: func(input *structs.OptimizationPostInput) {
+				input.Locations.Approaches = []string{"left", "right", "back"}
+			},
+			paths: []string{"locations.approaches[0]", "locations.approaches[1]", "locations.approaches[2]"},
+		},
+		{
+			name: "time windows",
+			modify: func(input *structs.OptimizationPostInput) {
+				input.Jobs[0].TimeWindows = [][]uint64{{10, 5}, {20, 10}}
+			},
+			paths: []string{"jobs[0].time_windows[0]", "jobs[0].time_windows[1]"},
+		},
+		{
+			name: "options",
+			modify: func(input *structs.OptimizationPostInput) {
+				input.Options.Objective.TravelCost = "fastest"
+				input.Options.Routing.Mode = &mode
+				input.Options.Routing.TruckSize = &truckSize
+			},
+			paths: []string{"options.objective.travel_cost", "options.routing.mode", "options.routing.truck_size"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			input := validInput()
+			tc.modify(input)
+			_, _, err := ValidateWithOptions(input, ValidateOptions{CollectAll: true})
+			if paths := errorPaths(t, err); !slices.Equal(paths, tc.paths) {
+				t.Errorf("expected errors at %v, got %v", tc.paths, paths)
+			}
+		})
+	}
+}
+
+func TestValidateAcceptsEmptyMode(t *testing.T) {
+	input := validInput()
+	empty := structs.RoutingMode("")
+	input.Options.Routing.Mode = &empty
+	normalized, _, err := Validate(input)
+	if err != nil {
+		t.Fatalf("expected an empty mode to be accepted, got %v", err)
+	}
+	if mode := normalized.Options.Routing.ModeOrDefault(); mode != structs.ModeCar {
+		t.Errorf("expected an empty mode to default to car, got %q", mode)
+	}
+}
+
+func TestValidateAdoptsLegacyModeOverEmptyMode(t *testing.T) {
+	input := validInput()
+	empty, truck := structs.RoutingMode(""), structs.RoutingMode("truck")
+	input.Options.Routing.Mode = &empty
+	input.Mode = &truck
+	normalized, warnings, err := Validate(input)
+	if err != nil {
+		t.Fatalf("expected the legacy mode to be accepted, got %v", err)
+	}
+	if mode := normalized.Options.Routing.ModeOrDefault(); mode != structs.ModeTruck {
+		t.Errorf("expected the legacy mode to be adopted, got %q", mode)
+	}
+	for _, warning := range warnings {
+		if warning.Code == CodeConflictingFields {
+			t.Errorf("expected no conflict with an empty mode, got %v", warning)
+		}
+	}
+}
+
+func TestValidateStopsAtFirstError(t *testing.T) {
+	input := validInput()
+	input.Locations.Approaches = []string{"left", "right", "back"}
+	_, _, err := Validate(input)
+	if paths := errorPaths(t, err); !slices.Equal(paths, []string{"locations.approaches[0]"}) {
+		t.Errorf("expected only the first approach to be reported, got %v", paths)
+	}
+}
+
+func TestValidateVehicleTimeWindowPath(t *testing.T) {
+	input := validInput()
+	input.Vehicles[0].TimeWindow = []uint64{10, 5}
+	_, _, err := Validate(input)
+	if paths := errorPaths(t, err); !slices.Equal(paths, []string{"vehicles[0].time_window"}) {
+		t.Errorf("expected the vehicle time window to be reported without index, got %v", paths)
+	}
+}
@@ -0,0 +1,248 @@
+package validations
+
+import (
+	"fmt"
+
+	structs "github.com/nextbillion-ai/nb-optimization-interface/structs"
+)
+
+type verifier struct {
+	durations [][]uint64
+	tasks     structs.TaskIndex
+	vehicles  map[uint64]structs.Vehicle
+	served    map[string]map[uint64]string // task type -> id -> path of the step serving it
+	errs      structs.ValidationErrors
+}
+
+// VerifySolution checks result against the request it answers, independently of the engine. It reports every
+// violation found: a task served more than once, a shipment delivered without its pickup earlier on the same
+// route, a load above the capacity, an arrival after the time windows of a task, a break or a shift, a task
+// needing skills its vehicle lacks, and more tasks on a route than max_tasks. Arrivals are recomputed from the
+// durations of matrix, or from the arrivals of the result when matrix has none, waiting for a time window to
+// open and never leaving the start before the shift begins. They are thus the earliest possible ones and
+// every lateness reported is a real one. input is expected to have passed Validate.
+func VerifySolution(input *structs.OptimizationPostInput, result *structs.HeraldResult, matrix *structs.Matrix) structs.ValidationErrors {
+	v := newVerifier(input, matrix)
+	vehicleRoutes := map[uint64]string{}
+	for i, route := range result.Routes {
+		path := fmt.Sprintf("routes[%d]", i)
+		if route.Vehicle == nil {
+			v.report(path+".vehicle", CodeUnknownVehicle, "route has no vehicle")
+			continue
+		}
+		vehicle, ok := v.vehicles[*route.Vehicle]
+		if !ok {
+			v.report(path+".vehicle", CodeUnknownVehicle, "vehicle %d is not a vehicle of the request", *route.Vehicle)
+			continue
+		}
+		if first, ok := vehicleRoutes[vehicle.Id]; ok {
+			v.report(path+".vehicle", CodeVehicleUsedTwice, "vehicle %d already drives %s", vehicle.Id, first)
+		}
+		vehicleRoutes[vehicle.Id] = path
+		v.verifyRoute(path, vehicle, route)
+	}
+	return v.errs
+}
+
+func newVerifier(input *structs.OptimizationPostInput, matrix *structs.Matrix) *verifier {
+	v := &verifier{
+		tasks:    structs.NewTaskIndex(input),
+		vehicles: make(map[uint64]structs.Vehicle, len(input.Vehicles)),
+		served: map[string]map[uint64]string{
+			structs.StepTypeJob:      {},
+			structs.StepTypePickup:   {},
+			structs.StepTypeDelivery: {},
+		},
+	}
+	if matrix != nil && len(matrix.Durations) > 0 {
+		v.durations = matrix.Durations
+	}
+	for _, vehicle := range input.Vehicles {
+		v.vehicles[vehicle.Id] = vehicle
+	}
+	return v
+}
+
+func (v *verifier) report(path string, code string, format string, args ...any) {
+	v.errs = append(v.errs, newError(path, code, format, args...))
+}
+
+func (v *verifier) verifyRoute(path string, vehicle structs.Vehicle, route structs.HeraldRoute) {
+	skills := make(map[uint64]bool, len(vehicle.Skills))
+	for _, skill := range vehicle.Skills {
+		skills[skill] = true
+	}
+	breaks := make(map[uint64]structs.Break, len(vehicle.Breaks))
+	for _, b := range vehicle.Breaks {
+		breaks[b.Id] = b
+	}
+
+	var jobIds []uint64
+	for _, step := range route.Steps {
+		if step.Type != nil && *step.Type == structs.StepTypeJob && step.Id != nil {
+			jobIds = append(jobIds, *step.Id)
+		}
+	}
+	load := v.tasks.InitialLoad(jobIds)
+	overloaded := !v.checkLoad(path+".steps", vehicle, load)
+
+	var now uint64
+	if len(vehicle.TimeWindow) == 2 {
+		now = vehicle.TimeWindow[0]
+	}
+	var previous *uint64
+	// timed turns false once the matrix can not time the route any further
+	timed := true
+	pickedUp := map[uint64]bool{}
+	taskCount := uint64(0)
+	for j, step := range route.Steps {
+		stepPath := fmt.Sprintf("%s.steps[%d]", path, j)
+		if step.Type == nil {
+			v.report(stepPath+".type", CodeInvalidStepType, "step has no type")
+			continue
+		}
+
+		var task structs.Task
+		var location *uint64
+		var name string
+		switch *step.Type {
+		case structs.StepTypeStart:
+			location = firstIndex(step.LocationIndex, vehicle.StartIndex)
+		case structs.StepTypeEnd:
+			location = firstIndex(step.LocationIndex, vehicle.EndIndex)
+		case structs.StepTypeBreak:
+			if step.Id == nil {
+				v.report(stepPath+".id", CodeUnknownStepId, "break step has no id")
+				continue
+			}
+			b, ok := breaks[*step.Id]
+			if !ok {
+				v.report(stepPath+".id", CodeUnknownStepId, "break %d is not a break of vehicle %d", *step.Id, vehicle.Id)
+				continue
+			}
+			task = structs.Task{Service: b.Service, TimeWindows: b.TimeWindows}
+			location = previous
+			name = fmt.Sprintf("break %d", b.Id)
+		case structs.StepTypeJob, structs.StepTypePickup, structs.StepTypeDelivery:
+			if step.Id == nil {
+				v.report(stepPath+".id", CodeUnknownStepId, "%s step has no id", *step.Type)
+				continue
+			}
+			var ok bool
+			if task, ok = v.tasks.Get(*step.Type, *step.Id); !ok {
+				v.report(stepPath+".id", CodeUnknownStepId, "%s %d is not a task of the request", *step.Type, *step.Id)
+				continue
+			}
+			name = fmt.Sprintf("%s %d", *step.Type, *step.Id)
+			locationIndex := task.LocationIndex
+			location = &locationIndex
+			taskCount++
+			v.verifyTask(stepPath, vehicle, skills, pickedUp, *step.Type, *step.Id, task)
+			load = load.Serve(task)
+			if !overloaded {
+				overloaded = !v.checkLoad(stepPath, vehicle, load)
+			}
+		default:
+			v.report(stepPath+".type", CodeInvalidStepType, "the step type %q is invalid", *step.Type)
+			continue
+		}
+
+		if !timed {
+			continue
+		}
+		arrival, ok := v.arrive(stepPath, now, previous, location, step)
+		if !ok {
+			timed = false
+			continue
+		}
+		if *step.Type == structs.StepTypeEnd {
+			if len(vehicle.TimeWindow) == 2 && arrival > vehicle.TimeWindow[1] {
+				v.report(stepPath, CodeTimeWindowMissed, "vehicle %d can not be back before %d, after the end %d of its shift", vehicle.Id, arrival, vehicle.TimeWindow[1])
+			}
+			continue
+		}
+		begin, violations := structs.ServiceStart(arrival, structs.VehicleStep{}, task.TimeWindows)
+		for _, violation := range violations {
+			if violation.Cause != nil && *violation.Cause == structs.ViolationDelay && violation.Duration != nil {
+				v.report(stepPath, CodeTimeWindowMissed, "%s is reached at %d at the earliest, %.0f seconds after its last time window closes", name, arrival, *violation.Duration)
+			}
+		}
+		now = begin + task.Service
+		if location != nil && (previous == nil || *previous != *location) {
+			now += task.Setup
+		}
+		if location != nil {
+			previous = location
+		}
+	}
+
+	for _, step := range route.Steps {
+		if step.Type != nil && *step.Type == structs.StepTypePickup && step.Id != nil && pickedUp[*step.Id] {
+			v.report(path+".steps", CodeShipmentOrder, "pickup %d is not followed by its delivery on vehicle %d", *step.Id, vehicle.Id)
+			pickedUp[*step.Id] = false
+		}
+	}
+	if vehicle.MaxTasks != nil && taskCount > *vehicle.MaxTasks {
+		v.report(path+".steps", CodeMaxTasksExceeded, "vehicle %d serves %d tasks, more than its max_tasks %d", vehicle.Id, taskCount, *vehicle.MaxTasks)
+	}
+}
+
+// verifyTask checks the served once, skills and shipment order constraints of a task step.
+// pickedUp tracks the pickups of the route still waiting for their delivery.
+func (v *verifier) verifyTask(path string, vehicle structs.Vehicle, skills map[uint64]bool, pickedUp map[uint64]bool, taskType string, id uint64, task structs.Task) {
+	if first, ok := v.served[taskType][id]; ok {
+		v.report(path+".id", CodeTaskServedTwice, "%s %d is already served at %s", taskType, id, first)
+	} else {
+		v.served[taskType][id] = path
+	}
+	for _, skill := range task.Skills {
+		if !skills[skill] {
+			v.report(path, CodeSkillMismatch, "%s %d needs skill %d which vehicle %d does not have", taskType, id, skill, vehicle.Id)
+		}
+	}
+	switch taskType {
+	case structs.StepTypePickup:
+		pickedUp[id] = true
+	case structs.StepTypeDelivery:
+		if !pickedUp[*task.Pickup] {
+			v.report(path, CodeShipmentOrder, "delivery %d is not preceded by its pickup %d on vehicle %d", id, *task.Pickup, vehicle.Id)
+		}
+		pickedUp[*task.Pickup] = false
+	}
+}
+
+// checkLoad reports a load above the capacity of vehicle. A vehicle without capacity is not limited.
+func (v *verifier) checkLoad(path string, vehicle structs.Vehicle, load structs.Load) bool {
+	if load.Exceeds(vehicle.Capacity) {
+		v.report(path, CodeCapacityExceeded, "the load %v of vehicle %d exceeds its capacity %v", []int64(load), vehicle.Id, vehicle.Capacity)
+		return false
+	}
+	return true
+}
+
+// arrive returns the earliest arrival at location when leaving previous at now
+func (v *verifier) arrive(path string, now uint64, previous *uint64, location *uint64, step structs.HeraldStep) (uint64, bool) {
+	if v.durations == nil {
+		if step.Arrival != nil && uint64(*step.Arrival) > now {
+			return uint64(*step.Arrival), true
+		}
+		return now, true
+	}
+	if previous == nil || location == nil {
+		return now, true
+	}
+	if *previous >= uint64(len(v.durations)) || *location >= uint64(len(v.durations[*previous])) {
+		v.report(path, CodeLocationIndexOutOfRange, "the duration matrix has no entry from location %d to location %d", *previous, *location)
+		return 0, false
+	}
+	return now + v.durations[*previous][*location], true
+}
+
+func firstIndex(indices ...*uint64) *uint64 {
+	for _, index := range indices {
+		if index != nil {
+			return index
+		}
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package validations
+
+import (
+	"slices"
+	"testing"
+
+	structs "github.com/nextbillion-ai/nb-optimization-interface/structs"
+)
+
+func step(stepType string, id uint64) structs.HeraldStep {
+	return structs.HeraldStep{Type: &stepType, Id: &id}
+}
+
+func route(vehicle uint64, steps ...structs.HeraldStep) structs.HeraldRoute {
+	return structs.HeraldRoute{Vehicle: &vehicle, Steps: steps}
+}
+
+func verificationCodes(errs structs.ValidationErrors) []string {
+	codes := make([]string, 0, len(errs))
+	for _, item := range errs {
+		codes = append(codes, item.Code)
+	}
+	return codes
+}
+
+func TestVerifySolutionVehicleWithoutCapacity(t *testing.T) {
+	input := &structs.OptimizationPostInput{
+		Jobs:     []structs.Job{{Id: 1, Delivery: []uint64{5}}},
+		Vehicles: []structs.Vehicle{{Id: 1}},
+	}
+	result := &structs.HeraldResult{Routes: []structs.HeraldRoute{route(1, step(structs.StepTypeJob, 1))}}
+	if errs := VerifySolution(input, result, nil); len(errs) > 0 {
+		t.Errorf("expected a vehicle without capacity to carry any load, got %v", errs)
+	}
+}
+
+func TestVerifySolution(t *testing.T) {
+	zero, one := uint64(0), uint64(1)
+	input := &structs.OptimizationPostInput{
+		Jobs: []structs.Job{
+			{Id: 1, LocationIndex: 1, TimeWindows: [][]uint64{{0, 5}}, Delivery: []uint64{3}},
+			{Id: 2, LocationIndex: 1, Skills: []uint64{9}},
+		},
+		Shipments: []structs.Shipment{{
+			Pickup:   &structs.ShipmentStep{Id: 3, LocationIndex: 1},
+			Delivery: &structs.ShipmentStep{Id: 4, LocationIndex: 0},
+			Amount:   []uint64{1},
+		}},
+		Vehicles: []structs.Vehicle{
+			{Id: 7, StartIndex: &zero, Capacity: []int64{3}, TimeWindow: []uint64{0, 100}},
+			{Id: 8, StartIndex: &zero, MaxTasks: &one},
+		},
+	}
+	matrix := &structs.Matrix{Durations: [][]uint64{{0, 10}, {10, 0}}}
+
+	cases := []struct {
+		name   string
+		routes []structs.HeraldRoute
+		codes  []string
+	}{
+		{
+			name:   "valid",
+			routes: []structs.HeraldRoute{route(7, step(structs.StepTypeStart, 0), step(structs.StepTypePickup, 3), step(structs.StepTypeDelivery, 4))},
+		},
+		{
+			name:   "late",
+			routes: []structs.HeraldRoute{route(7, step(structs.StepTypeStart, 0), step(structs.StepTypeJob, 1))},
+			codes:  []string{CodeTimeWindowMissed},
+		},
+		{
+			name:   "vehicle without capacity",
+			routes: []structs.HeraldRoute{route(8, step(structs.StepTypeJob, 1))},
+		},
+		{
+			name:   "over capacity",
+			routes: []structs.HeraldRoute{route(7, step(structs.StepTypePickup, 3), step(structs.StepTypeJob, 1), step(structs.StepTypeDelivery, 4))},
+			codes:  []string{CodeCapacityExceeded},
+		},
+		{
+			name:   "delivery before pickup",
+			routes: []structs.HeraldRoute{route(7, step(structs.StepTypeDelivery, 4), step(structs.StepTypePickup, 3))},
+			codes:  []string{CodeShipmentOrder, CodeShipmentOrder},
+		},
+		{
+			name:   "served twice, missing skill and max tasks",
+			routes: []structs.HeraldRoute{route(7, step(structs.StepTypeJob, 2)), route(8, step(structs.StepTypeJob, 2), step(structs.StepTypePickup, 3), step(structs.StepTypeDelivery, 4))},
+			codes:  []string{CodeSkillMismatch, CodeTaskServedTwice, CodeSkillMismatch, CodeMaxTasksExceeded},
+		},
+		{
+			name:   "unknown vehicle and task",
+			routes: []structs.HeraldRoute{route(6), route(7, step(structs.StepTypeJob, 42))},
+			codes:  []string{CodeUnknownVehicle, CodeUnknownStepId},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := VerifySolution(input, &structs.HeraldResult{Routes: tc.routes}, matrix)
+			if codes := verificationCodes(errs); !slices.Equal(codes, tc.codes) {
+				t.Errorf("expected %v, got %v", tc.codes, errs)
+			}
+		})
+	}
+}